@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,6 +16,9 @@ import (
 
 const geminiAPIURL = "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent?key="
 
+// conventionalSubjectRegex splits "type(scope)!: description" into its parts
+var conventionalSubjectRegex = regexp.MustCompile(`^(\w+)(\([^)]*\))?(!?):\s*(.*)$`)
+
 // SessionManager implements the AIProvider interface
 type SessionManager struct {
 	currentSession *Session
@@ -64,32 +68,30 @@ func NewSessionManager(config interfaces.ConfigManager) interfaces.AIProvider {
 }
 
 // GenerateCommitMessage generates a commit message based on git diff and context
-func (sm *SessionManager) GenerateCommitMessage(diff string, files []string, branchName, additionalContext string) (string, error) {
+func (sm *SessionManager) GenerateCommitMessage(req interfaces.CommitRequest) (string, error) {
 	if err := sm.validateConfig(); err != nil {
 		return "", err
 	}
 
-	prompt := sm.buildCommitPrompt(diff, files, branchName, additionalContext)
+	prompt := sm.buildCommitPrompt(req)
 
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("GEMINI_API_KEY environment variable is not set")
-	}
-
-	response, err := sm.callGeminiAPI(prompt, apiKey)
+	response, err := sm.complete(prompt)
 	if err != nil {
-		return "", fmt.Errorf("AI API call failed: %v", err)
+		return "", err
 	}
 
 	// Clean up the response
-	message := strings.TrimSpace(response)
-	message = strings.Trim(message, "`\"'")
+	message := strings.Trim(response, "`\"'")
 
 	// Validate the generated message
 	if message == "" {
 		return "", fmt.Errorf("AI generated an empty commit message")
 	}
 
+	if req.Scope != "" {
+		message = applyScope(message, req.Scope)
+	}
+
 	// Add interaction to session
 	sm.AddInteraction("commit", prompt, message, "")
 
@@ -97,8 +99,9 @@ func (sm *SessionManager) GenerateCommitMessage(diff string, files []string, bra
 }
 
 // buildCommitPrompt constructs the prompt for commit message generation
-func (sm *SessionManager) buildCommitPrompt(diff string, files []string, branchName, additionalContext string) string {
+func (sm *SessionManager) buildCommitPrompt(req interfaces.CommitRequest) string {
 	var prompt strings.Builder
+	branchName := req.Branch
 
 	aiConfig := sm.config.GetAI()
 
@@ -123,26 +126,243 @@ func (sm *SessionManager) buildCommitPrompt(diff string, files []string, branchN
 		prompt.WriteString(fmt.Sprintf("Branch: %s\n", branchName))
 	}
 
-	if len(files) > 0 {
-		prompt.WriteString(fmt.Sprintf("Modified files: %s\n", strings.Join(files, ", ")))
-	}
+	writeFileSummaries(&prompt, req.Diff)
 
-	if additionalContext != "" {
-		prompt.WriteString(fmt.Sprintf("Context: %s\n", additionalContext))
+	if req.Context != "" {
+		prompt.WriteString(fmt.Sprintf("Context: %s\n", req.Context))
 	}
 
 	prompt.WriteString("\nGit diff:\n")
-	prompt.WriteString(diff)
+	sm.writeDiffByConfig(&prompt, req.Diff)
 	prompt.WriteString("\n\nRequirements:")
 	prompt.WriteString("\n- Use conventional commit format if applicable")
 	prompt.WriteString("\n- Be specific about what changed")
 	prompt.WriteString("\n- Keep it under 50 characters for the subject line")
 	prompt.WriteString("\n- Focus on the 'why' and 'what', not the 'how'")
 	prompt.WriteString("\n- Do not include file names unless essential")
+	if req.Scope != "" {
+		prompt.WriteString(fmt.Sprintf("\n- The scope MUST be %q, i.e. the subject starts with \"<type>(%s): \"", req.Scope, req.Scope))
+	}
+
+	return prompt.String()
+}
+
+// GenerateSquashMessage synthesizes a single commit message for a series of commits being squashed
+func (sm *SessionManager) GenerateSquashMessage(messages []string, diff *interfaces.Diff, branchName string) (string, error) {
+	if err := sm.validateConfig(); err != nil {
+		return "", err
+	}
+
+	prompt := sm.buildSquashPrompt(messages, diff, branchName)
+
+	response, err := sm.complete(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	// Clean up the response, keeping the body intact
+	message := trimCodeFence(response)
+
+	if message == "" {
+		return "", fmt.Errorf("AI generated an empty squash message")
+	}
+
+	sm.AddInteraction("squash", prompt, message, "")
+
+	return message, nil
+}
+
+// buildSquashPrompt constructs the prompt for squash message synthesis
+func (sm *SessionManager) buildSquashPrompt(messages []string, diff *interfaces.Diff, branchName string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("The following commits are being squashed into a single commit. ")
+	prompt.WriteString("Write one coherent commit message that describes the combined change.\n\n")
+
+	if branchName != "" {
+		prompt.WriteString(fmt.Sprintf("Branch: %s\n", branchName))
+	}
+
+	writeFileSummaries(&prompt, diff)
+
+	prompt.WriteString("\nOriginal commit messages (oldest first):\n")
+	for i, message := range messages {
+		prompt.WriteString(fmt.Sprintf("%d. %s\n", i+1, strings.ReplaceAll(strings.TrimSpace(message), "\n", "\n   ")))
+	}
+
+	prompt.WriteString("\nCombined diff:\n")
+	sm.writeDiffByConfig(&prompt, diff)
+	prompt.WriteString("\n\nRequirements:")
+	prompt.WriteString("\n- First line: a conventional commit subject under 72 characters")
+	prompt.WriteString("\n- Then a blank line followed by a bulleted body (\"- \") summarising the notable changes")
+	prompt.WriteString("\n- Merge duplicate or fix-up commits (\"wip\", \"fix typo\") into the relevant bullet")
+	prompt.WriteString("\n- Do not list commit hashes and do not wrap the message in quotes or code fences")
+
+	return prompt.String()
+}
+
+// GenerateReport summarises a period of work history, given as an outline of
+// commits grouped by repository and scope, in Markdown or Slack format
+func (sm *SessionManager) GenerateReport(title, outline, format string) (string, error) {
+	if err := sm.validateConfig(); err != nil {
+		return "", err
+	}
+
+	prompt := buildReportPrompt(title, outline, format)
+
+	response, err := sm.complete(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	report := trimCodeFence(response)
+
+	if report == "" {
+		return "", fmt.Errorf("AI generated an empty report")
+	}
+
+	sm.AddInteraction("report", prompt, report, "")
+
+	return report, nil
+}
+
+// buildReportPrompt constructs the prompt for a standup or weekly report
+func buildReportPrompt(title, outline, format string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("Write a concise \"%s\" for a software developer, based on the commits below. ", title))
+	prompt.WriteString("It will be shared with teammates, so describe outcomes rather than individual commits.\n\n")
+	prompt.WriteString("Commits grouped by repository and scope:\n")
+	prompt.WriteString(outline)
+
+	prompt.WriteString("\nRequirements:")
+	prompt.WriteString("\n- Group related work under short headings per repository or area")
+	prompt.WriteString("\n- Use brief bullet points; merge small fix-ups into the work they belong to")
+	prompt.WriteString("\n- Do not invent work that is not in the commits and do not list commit hashes")
+	if format == "slack" {
+		prompt.WriteString("\n- Format for Slack: *bold* for headings, • for bullets, no Markdown headings or tables")
+	} else {
+		prompt.WriteString(fmt.Sprintf("\n- Format as Markdown, starting with the heading \"## %s\"", title))
+	}
+	prompt.WriteString("\n- Do not wrap the report in code fences")
+
+	return prompt.String()
+}
+
+// ProposeCommitGroups asks the AI to cluster diff chunks into logical commits
+func (sm *SessionManager) ProposeCommitGroups(chunks []string, branchName string) ([]interfaces.CommitGroup, error) {
+	if err := sm.validateConfig(); err != nil {
+		return nil, err
+	}
+
+	prompt := sm.buildSplitPrompt(chunks, branchName)
+
+	response, err := sm.complete(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := parseCommitGroups(response)
+	if err != nil {
+		return nil, err
+	}
+
+	sm.AddInteraction("split", prompt, response, "")
+
+	return groups, nil
+}
+
+// buildSplitPrompt constructs the prompt for clustering chunks into commits
+func (sm *SessionManager) buildSplitPrompt(chunks []string, branchName string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("The following staged changes are too large for a single commit. ")
+	prompt.WriteString("Group the numbered chunks into a small number of logical, self-contained commits ")
+	prompt.WriteString("and propose a conventional commit message for each group.\n\n")
+
+	if branchName != "" {
+		prompt.WriteString(fmt.Sprintf("Branch: %s\n\n", branchName))
+	}
+
+	for i, chunk := range chunks {
+		prompt.WriteString(fmt.Sprintf("### Chunk %d\n%s\n\n", i, chunk))
+	}
+
+	prompt.WriteString("Requirements:")
+	prompt.WriteString("\n- Every chunk number must appear in exactly one group")
+	prompt.WriteString("\n- Order the groups so that each commit builds on the previous ones")
+	prompt.WriteString("\n- Keep each subject line under 50 characters")
+	prompt.WriteString("\n- Respond with JSON only, no prose, in the form:")
+	prompt.WriteString("\n  [{\"message\": \"feat(api): add endpoint\", \"chunks\": [0, 2]}]")
 
 	return prompt.String()
 }
 
+// parseCommitGroups extracts the JSON array of commit groups from an AI response
+func parseCommitGroups(response string) ([]interfaces.CommitGroup, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("AI response did not contain a list of commit groups")
+	}
+
+	var groups []interfaces.CommitGroup
+	if err := json.Unmarshal([]byte(response[start:end+1]), &groups); err != nil {
+		return nil, fmt.Errorf("error parsing commit groups: %v", err)
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("AI proposed no commit groups")
+	}
+
+	return groups, nil
+}
+
+// applyScope forces the scope of a conventional commit subject, e.g.
+// "feat: add invoices" or "feat(api): add invoices" become "feat(billing): add invoices".
+// Subjects that are not in conventional form are left untouched.
+func applyScope(message, scope string) string {
+	subject, body, _ := strings.Cut(message, "\n")
+	match := conventionalSubjectRegex.FindStringSubmatch(subject)
+	if match == nil {
+		return message
+	}
+
+	subject = fmt.Sprintf("%s(%s)%s: %s", match[1], scope, match[3], match[4])
+	if body == "" {
+		return subject
+	}
+	return subject + "\n" + body
+}
+
+// writeFileSummaries lists the changed files with their status and line counts
+func writeFileSummaries(prompt *strings.Builder, diff *interfaces.Diff) {
+	if diff.IsEmpty() {
+		return
+	}
+
+	prompt.WriteString("Modified files:\n")
+	for _, file := range diff.Files {
+		prompt.WriteString(fmt.Sprintf("- %s\n", file.Summary()))
+	}
+}
+
+// writeDiffByConfig renders the diff grouped by the directory config that
+// applies to each file, so nested overrides (language, standards, size limits)
+// are honoured per group. Without overrides the diff is written as-is.
+func (sm *SessionManager) writeDiffByConfig(prompt *strings.Builder, diff *interfaces.Diff) {
+	groups := sm.config.GroupByConfig(diff.Paths())
+	if len(groups) == 1 && groups[0].Dir == "" {
+		prompt.WriteString(diff.PromptText(groups[0].AI.MaxFileDiffBytes))
+		return
+	}
+
+	for _, group := range groups {
+		prompt.WriteString("\n" + group.Heading())
+		prompt.WriteString(diff.Only(group.Files).PromptText(group.AI.MaxFileDiffBytes))
+	}
+}
+
 // AddInteraction adds an interaction to the current session
 func (sm *SessionManager) AddInteraction(interactionType, prompt, response, feedback string) {
 	interaction := interfaces.AIInteraction{
@@ -183,6 +403,29 @@ func (sm *SessionManager) GetContextualPrompt(basePrompt string) string {
 	return contextBuilder.String()
 }
 
+// complete sends a prompt to the configured model and returns the trimmed response
+func (sm *SessionManager) complete(prompt string) (string, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return "", fmt.Errorf("GEMINI_API_KEY environment variable is not set")
+	}
+
+	response, err := sm.callGeminiAPI(prompt, apiKey)
+	if err != nil {
+		return "", fmt.Errorf("AI API call failed: %v", err)
+	}
+
+	return strings.TrimSpace(response), nil
+}
+
+// trimCodeFence removes a code fence the model wrapped around its whole response
+func trimCodeFence(response string) string {
+	response = strings.TrimPrefix(response, "```markdown")
+	response = strings.TrimPrefix(response, "```")
+	response = strings.TrimSuffix(response, "```")
+	return strings.TrimSpace(response)
+}
+
 // callGeminiAPI makes a request to the Gemini API
 func (sm *SessionManager) callGeminiAPI(prompt, apiKey string) (string, error) {
 	request := GeminiRequest{
@@ -237,14 +480,9 @@ func (sm *SessionManager) AnalyzeCode(code, analysisType string) (string, error)
 
 	prompt := sm.buildAnalysisPrompt(code, analysisType)
 
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("GEMINI_API_KEY environment variable is not set")
-	}
-
-	response, err := sm.callGeminiAPI(prompt, apiKey)
+	response, err := sm.complete(prompt)
 	if err != nil {
-		return "", fmt.Errorf("AI analysis failed: %v", err)
+		return "", err
 	}
 
 	// Add interaction to session
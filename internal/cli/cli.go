@@ -0,0 +1,54 @@
+// Package cli implements the positional subcommands of the codegenius binary
+// (e.g. `codegenius reword HEAD`), complementing the flag-based modes in main.
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// Command is the signature shared by all subcommands
+type Command func(service *interfaces.Service, args []string) error
+
+// StandaloneCommand is a subcommand that runs without building the service
+type StandaloneCommand func(args []string) error
+
+// Commands maps subcommand names to their implementations
+var Commands = map[string]Command{
+	"reword":    Reword,
+	"squash":    Squash,
+	"split":     Split,
+	"history":   History,
+	"report":    Report,
+	"timesheet": Timesheet,
+}
+
+// Standalone maps subcommands that must work even when the service cannot be
+// built, e.g. because the configuration is invalid
+var Standalone = map[string]StandaloneCommand{
+	"config": Config,
+}
+
+// stdin is shared so buffered input is not lost between prompts
+var stdin = bufio.NewReader(os.Stdin)
+
+// prompt prints a question and returns the trimmed answer
+func prompt(question string) string {
+	fmt.Print(question)
+	answer, _ := stdin.ReadString('\n')
+	return strings.TrimSpace(answer)
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(question string) bool {
+	switch strings.ToLower(prompt(question + " (y/n): ")) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/config"
+)
+
+// Config inspects, checks and edits the configuration. It runs without
+// building the service so that a broken configuration can still be fixed.
+//
+//	codegenius config show [--origin]
+//	codegenius config validate
+//	codegenius config get <key>
+//	codegenius config set [--global] <key> <value>
+//	codegenius config add [--global] <key> <value>
+//	codegenius config edit [--global]
+//	codegenius config migrate
+func Config(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: codegenius config <show|validate|get|set|add|edit|migrate>")
+	}
+
+	manager := config.NewManager()
+	switch args[0] {
+	case "show":
+		return configShow(manager, args[1:])
+	case "validate":
+		return configValidate(manager)
+	case "get":
+		return configGet(manager, args[1:])
+	case "set", "add":
+		return configSet(manager, args[0], args[1:])
+	case "edit":
+		return configEdit(manager, args[1:])
+	case "migrate":
+		return configMigrate(manager)
+	default:
+		return fmt.Errorf("unknown config command %q (available: show, validate, get, set, add, edit, migrate)", args[0])
+	}
+}
+
+// configShow prints every effective setting, optionally with the layer it came from
+func configShow(manager *config.Manager, args []string) error {
+	flags := flag.NewFlagSet("config show", flag.ContinueOnError)
+	origin := flags.Bool("origin", false, "Show where each value was set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := manager.Load(); err != nil {
+		return fmt.Errorf("failed to load configuration: %v (run 'codegenius config validate' for details)", err)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, value := range manager.Describe() {
+		if *origin {
+			// Like `git config --show-origin`, the origin comes first
+			fmt.Fprintf(writer, "%s\t%s\t= %s\n", value.Origin, value.Key, value.Value)
+		} else {
+			fmt.Fprintf(writer, "%s\t= %s\n", value.Key, value.Value)
+		}
+	}
+	return writer.Flush()
+}
+
+// configValidate reports every problem in the configuration and fails when
+// there is an error, so it can gate CI. Warnings alone do not fail.
+func configValidate(manager *config.Manager) error {
+	issues := manager.Validate()
+	if len(issues) == 0 {
+		fmt.Println("✅ Configuration is valid")
+		return nil
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "%s\n", issue)
+	}
+	if !config.HasErrors(issues) {
+		fmt.Printf("⚠️  Configuration is valid with %d warning(s)\n", len(issues))
+		return nil
+	}
+	return fmt.Errorf("configuration has %d problem(s)", len(issues))
+}
+
+// configMigrate upgrades every config file to the current format, keeping a
+// backup of each file it rewrites
+func configMigrate(manager *config.Manager) error {
+	results := manager.Migrate()
+	if len(results) == 0 {
+		fmt.Println("💡 No configuration files found.")
+		return nil
+	}
+
+	failed := 0
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Fprintf(os.Stderr, "❌ %v\n", result.Err)
+		case result.Backup == "":
+			fmt.Printf("✅ %s is already at version %d\n", result.Path, result.To)
+		default:
+			fmt.Printf("🔄 %s: version %d → %d (backup: %s)\n", result.Path, result.From, result.To, result.Backup)
+			for _, applied := range result.Applied {
+				fmt.Printf("   • %s\n", applied)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d file(s) could not be migrated", failed)
+	}
+	return nil
+}
+
+// configGet prints the effective value of a key
+func configGet(manager *config.Manager, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: codegenius config get <key>")
+	}
+	if err := manager.Load(); err != nil {
+		return fmt.Errorf("failed to load configuration: %v", err)
+	}
+
+	values, err := manager.Get(args[0])
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		fmt.Println(value)
+	}
+	return nil
+}
+
+// configSet writes one value (set) or appends to a list (add) in the
+// repository config file, or the global one with --global
+func configSet(manager *config.Manager, action string, args []string) error {
+	flags := flag.NewFlagSet("config "+action, flag.ContinueOnError)
+	global := flags.Bool("global", false, "Change the global user config instead of the repository config")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("usage: codegenius config %s [--global] <key> <value>", action)
+	}
+
+	key, value := flags.Arg(0), flags.Arg(1)
+	path := manager.FilePath(*global)
+
+	var err error
+	if action == "add" {
+		// Seed a new list with the inherited items so they are not dropped
+		if loadErr := manager.Load(); loadErr != nil {
+			return fmt.Errorf("failed to load configuration: %v", loadErr)
+		}
+		err = config.AddValue(path, key, value, manager.ListValues(key))
+	} else {
+		err = config.SetValue(path, key, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Updated %s in %s\n", key, path)
+	return nil
+}
+
+// configEdit opens a config file in $EDITOR and validates it before saving,
+// offering to reopen the editor while there are problems
+func configEdit(manager *config.Manager, args []string) error {
+	flags := flag.NewFlagSet("config edit", flag.ContinueOnError)
+	global := flags.Bool("global", false, "Edit the global user config instead of the repository config")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	path := manager.FilePath(*global)
+	original, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading %s: %v", path, err)
+	}
+
+	// Edit a copy so an invalid result never replaces the real file
+	tmp, err := os.CreateTemp("", "codegenius-config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(original); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing temporary file: %v", err)
+	}
+	tmp.Close()
+
+	// $EDITOR may carry arguments, e.g. "code --wait"
+	editorArgs := strings.Fields(os.Getenv("EDITOR"))
+	if len(editorArgs) == 0 {
+		editorArgs = []string{"nano"}
+	}
+
+	for {
+		cmd := exec.Command(editorArgs[0], append(editorArgs[1:], tmp.Name())...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("error running editor: %v", err)
+		}
+
+		issues := config.ValidateFile(tmp.Name())
+		for _, issue := range issues {
+			issue.File = path
+			fmt.Fprintf(os.Stderr, "%s\n", issue)
+		}
+		if !config.HasErrors(issues) {
+			break
+		}
+		if !confirm("⚠️  The configuration has problems. Edit again?") {
+			fmt.Println("🚫 Changes discarded.")
+			return nil
+		}
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("error reading edited file: %v", err)
+	}
+	if string(edited) == string(original) {
+		fmt.Println("💡 No changes made.")
+		return nil
+	}
+	if err := config.WriteFile(path, edited); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Saved %s\n", path)
+	return nil
+}
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/history"
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// History queries and manages the work history.
+//
+//	codegenius history show [--since <date>] [--until <date>] [--month <month>] [--week <week>]
+//	                        [--author <a>] [--repo <r>] [--branch <b>] [--type <t>] [--scope <s>]
+//	                        [--grep <text>] [--all-repos]
+//	codegenius history export --format csv|jsonl|md [--output <file>] [history filters]
+//	codegenius history import [--since <date>] [--author <pattern>] [--all]
+//	codegenius history prune [--max-age-days <n>] [--max-entries <n>] [--subject-only] [--all-repos] [--dry-run]
+//	codegenius history delete <id|sha>...
+//	codegenius history purge [--repo <path|name>] [--yes]
+func History(service *interfaces.Service, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: codegenius history <show|export|import|prune|delete|purge>")
+	}
+
+	switch args[0] {
+	case "show":
+		return historyShow(service, args[1:])
+	case "export":
+		return historyExport(service, args[1:])
+	case "import":
+		return historyImport(service, args[1:])
+	case "prune":
+		return historyPrune(service, args[1:])
+	case "delete":
+		return historyDelete(service, args[1:])
+	case "purge":
+		return historyPurge(service, args[1:])
+	default:
+		return fmt.Errorf("unknown history command %q (available: show, export, import, prune, delete, purge)", args[0])
+	}
+}
+
+// queryFlags registers the history filter flags on a flag set and returns
+// the query they fill in
+func queryFlags(flags *flag.FlagSet) (*interfaces.HistoryQuery, *string) {
+	q := &interfaces.HistoryQuery{}
+	flags.StringVar(&q.Since, "since", "", "Only entries on or after this date, e.g. 2024-12-01, yesterday, \"last week\"")
+	flags.StringVar(&q.Until, "until", "", "Only entries on or before this date")
+	flags.StringVar(&q.Period, "month", "", "Only entries in this month or year, e.g. \"Dec 2024\", 2024-12, 2024")
+	week := flags.String("week", "", "Only entries in this ISO week, e.g. 2024-W50")
+	flags.StringVar(&q.Author, "author", "", "Only entries whose author contains this text")
+	flags.StringVar(&q.Repo, "repo", "", "Only entries from this repository (name or path)")
+	flags.StringVar(&q.Branch, "branch", "", "Only entries committed on this branch")
+	flags.StringVar(&q.Type, "type", "", "Only conventional commits of this type, e.g. feat")
+	flags.StringVar(&q.Scope, "scope", "", "Only conventional commits with this scope")
+	flags.StringVar(&q.Text, "grep", "", "Only entries whose message contains this text")
+	flags.BoolVar(&q.AllRepos, "all-repos", false, "Search every repository in the central history store")
+	return q, week
+}
+
+// resolveWeek folds --week into the query period, rejecting --week with --month
+func resolveWeek(q *interfaces.HistoryQuery, week string) error {
+	if week == "" {
+		return nil
+	}
+	if q.Period != "" {
+		return fmt.Errorf("--week and --month cannot be combined")
+	}
+	q.Period = week
+	return nil
+}
+
+// resolveAllRepos makes a command cover every repository when the central
+// store is enabled, unless --all-repos was given explicitly
+func resolveAllRepos(flags *flag.FlagSet, q *interfaces.HistoryQuery, historyConfig interfaces.HistoryConfig) {
+	allReposSet := false
+	flags.Visit(func(f *flag.Flag) {
+		allReposSet = allReposSet || f.Name == "all-repos"
+	})
+	if !allReposSet {
+		q.AllRepos = historyConfig.CentralStore
+	}
+}
+
+// historyShow prints the entries matching the given filters
+func historyShow(service *interfaces.Service, args []string) error {
+	flags := flag.NewFlagSet("history show", flag.ContinueOnError)
+	q, week := queryFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := resolveWeek(q, *week); err != nil {
+		return err
+	}
+
+	if err := service.History.Load(); err != nil {
+		return fmt.Errorf("failed to load work history: %v", err)
+	}
+	return service.History.DisplayQuery(*q)
+}
+
+// historyExport writes the entries matching the given filters to a file or stdout
+func historyExport(service *interfaces.Service, args []string) error {
+	flags := flag.NewFlagSet("history export", flag.ContinueOnError)
+	format := flags.String("format", "csv", "Output format: "+strings.Join(history.ExportFormats, ", "))
+	output := flags.String("output", "", "Write to this file instead of stdout")
+	q, week := queryFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := resolveWeek(q, *week); err != nil {
+		return err
+	}
+
+	if err := service.History.Load(); err != nil {
+		return fmt.Errorf("failed to load work history: %v", err)
+	}
+	entries, err := service.History.Query(*q)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return history.Export(os.Stdout, entries, *format)
+	}
+
+	var exported bytes.Buffer
+	if err := history.Export(&exported, entries, *format); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*output, exported.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", *output, err)
+	}
+	fmt.Printf("✅ Exported %d entries to %s\n", len(entries), *output)
+	return nil
+}
+
+// historyImport backfills the work history from git log, skipping commits
+// that are already recorded. By default only your own commits are imported.
+func historyImport(service *interfaces.Service, args []string) error {
+	flags := flag.NewFlagSet("history import", flag.ContinueOnError)
+	since := flags.String("since", "", "Only import commits after this date, e.g. 2024-01-01 or \"3 months ago\"")
+	author := flags.String("author", "", "Only import commits whose author matches this pattern (default: your git user.email)")
+	all := flags.Bool("all", false, "Import commits from every author")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case *all:
+		*author = ""
+	case *author == "":
+		*author = currentUserEmail(service.Git)
+	}
+
+	commits, err := service.Git.GetLog(*since, *author)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		if *author != "" {
+			fmt.Printf("💡 No commits by %s found (use --all to import every author).\n", *author)
+		} else {
+			fmt.Println("💡 No matching commits found.")
+		}
+		return nil
+	}
+
+	root, err := service.Git.GetRepoRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := service.History.Load(); err != nil {
+		return fmt.Errorf("failed to load work history: %v", err)
+	}
+	added, err := service.History.Import(commits, root)
+	if err != nil {
+		return fmt.Errorf("failed to import work history: %v", err)
+	}
+
+	fmt.Printf("✅ Imported %d commit(s) into work history (%d already recorded)\n", added, len(commits)-added)
+	return nil
+}
+
+// historyPrune applies the retention settings, or the limits given as flags,
+// to the work history
+func historyPrune(service *interfaces.Service, args []string) error {
+	settings := service.Config.GetHistory()
+	flags := flag.NewFlagSet("history prune", flag.ContinueOnError)
+	maxAge := flags.Int("max-age-days", settings.MaxAgeDays, "Remove entries older than this many days (default: history.max_age_days)")
+	maxEntries := flags.Int("max-entries", settings.MaxEntries, "Keep only this many of the newest entries (default: history.max_entries)")
+	subjectOnly := flags.Bool("subject-only", settings.SubjectOnly, "Strip commit message bodies, keeping subject lines (default: history.subject_only)")
+	allRepos := flags.Bool("all-repos", false, "Prune the central history store instead of this repository's history")
+	dryRun := flags.Bool("dry-run", false, "Show what would be pruned without changing anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *maxAge < 0 || *maxEntries < 0 {
+		return fmt.Errorf("--max-age-days and --max-entries must not be negative")
+	}
+
+	retention := interfaces.HistoryConfig{MaxAgeDays: *maxAge, MaxEntries: *maxEntries, SubjectOnly: *subjectOnly}.Retention()
+	if retention == (interfaces.Retention{}) {
+		fmt.Println("💡 No retention limits set. Pass --max-age-days, --max-entries or --subject-only, or set history.max_age_days, history.max_entries or history.subject_only.")
+		return nil
+	}
+
+	removed, trimmed, err := service.History.Prune(retention, *allRepos, *dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to prune work history: %v", err)
+	}
+
+	switch {
+	case removed == 0 && trimmed == 0:
+		fmt.Println("✅ Nothing to prune.")
+	case *dryRun:
+		fmt.Printf("🔍 Would remove %d entries and strip the body of %d\n", removed, trimmed)
+	default:
+		fmt.Printf("✅ Removed %d entries and stripped the body of %d\n", removed, trimmed)
+	}
+	return nil
+}
+
+// historyDelete removes single entries by ID or commit SHA
+func historyDelete(service *interfaces.Service, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: codegenius history delete <id|sha>... (IDs are shown by 'codegenius history show')")
+	}
+
+	if err := service.History.Load(); err != nil {
+		return fmt.Errorf("failed to load work history: %v", err)
+	}
+	deleted, err := service.History.Delete(args)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range deleted {
+		fmt.Printf("🗑️  Deleted %s %s\n", entry.ID, strings.SplitN(entry.Summary, "\n", 2)[0])
+	}
+	return nil
+}
+
+// historyPurge removes every entry of a repository, by default the current
+// one, from the work history and the central store
+func historyPurge(service *interfaces.Service, args []string) error {
+	flags := flag.NewFlagSet("history purge", flag.ContinueOnError)
+	repo := flags.String("repo", "", "Repository path or directory name to purge (default: the current repository)")
+	yes := flags.Bool("yes", false, "Do not ask for confirmation")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	root, rootErr := service.Git.GetRepoRoot()
+	target := *repo
+	switch {
+	case target == "" && rootErr != nil:
+		return fmt.Errorf("not in a git repository, pass --repo: %v", rootErr)
+	case target == "":
+		target = root
+	case strings.ContainsAny(target, `/\`) || target == "." || target == "..":
+		absolute, err := filepath.Abs(target)
+		if err != nil {
+			return fmt.Errorf("error resolving %s: %v", target, err)
+		}
+		target = absolute
+	}
+
+	// The current repository's own history file goes entirely
+	current := rootErr == nil && (target == root || target == filepath.Base(root))
+	if current {
+		target = root
+	}
+
+	if !*yes && !confirm(fmt.Sprintf("⚠️  Permanently delete the work history of %s?", target)) {
+		fmt.Println("🚫 Purge cancelled.")
+		return nil
+	}
+
+	removed, err := service.History.Purge(target, current)
+	if err != nil {
+		return fmt.Errorf("failed to purge work history: %v", err)
+	}
+	fmt.Printf("✅ Removed %d entries of %s\n", removed, target)
+	return nil
+}
+
+// currentUserEmail returns the configured git user.email, or "" when unset
+func currentUserEmail(repo interfaces.RepositoryInfo) string {
+	signoff, err := repo.GetSignoff()
+	if err != nil {
+		return ""
+	}
+	start, end := strings.LastIndex(signoff.Value, "<"), strings.LastIndex(signoff.Value, ">")
+	if start == -1 || end <= start+1 {
+		return ""
+	}
+	return signoff.Value[start+1 : end]
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/history"
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+	"github.com/Shubhpreet-Rana/codegenius/internal/report"
+)
+
+// Report summarises recent work history for a standup or weekly update. The
+// AI writes the summary; with --no-ai, or when the AI is unavailable, the
+// commits are listed grouped by type instead. The report goes to stdout so it
+// can be piped or pasted.
+//
+//	codegenius report --standup|--weekly [--format markdown|slack] [--no-ai] [history filters]
+func Report(service *interfaces.Service, args []string) error {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	standup := flags.Bool("standup", false, "Report yesterday and today so far")
+	weekly := flags.Bool("weekly", false, "Report this week so far")
+	format := flags.String("format", report.FormatMarkdown, "Output format: markdown or slack")
+	noAI := flags.Bool("no-ai", false, "List commits grouped by type instead of asking the AI")
+	q, week := queryFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := resolveWeek(q, *week); err != nil {
+		return err
+	}
+	if !report.ValidFormat(*format) {
+		return fmt.Errorf("unknown format %q (available: markdown, slack)", *format)
+	}
+
+	now := time.Now()
+	var title string
+	switch {
+	case *standup && *weekly:
+		return fmt.Errorf("--standup and --weekly cannot be combined")
+	case *standup:
+		if q.Since == "" {
+			q.Since = "yesterday"
+		}
+		title = "Standup — " + now.Format("Monday, 02 Jan 2006")
+	case *weekly:
+		if q.Since == "" && q.Period == "" {
+			q.Since = "this week"
+		}
+		weekStart, _ := history.ParseDate("this week", now, false)
+		title = "Weekly Report — week of " + weekStart.Format("02 Jan 2006")
+	case q.Since != "" || q.Until != "" || q.Period != "":
+		title = "Work Report"
+	default:
+		return fmt.Errorf("usage: codegenius report --standup|--weekly [--format markdown|slack] [--no-ai]")
+	}
+
+	// Reports cover every repository when the central store is enabled
+	resolveAllRepos(flags, q, service.Config.GetHistory())
+
+	if err := service.History.Load(); err != nil {
+		return fmt.Errorf("failed to load work history: %v", err)
+	}
+	entries, err := service.History.Query(*q)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, "💡 No commits found for this period.")
+		return nil
+	}
+
+	if !*noAI {
+		fmt.Fprintf(os.Stderr, "🤖 Summarising %d commit(s)...\n", len(entries))
+		summary, err := service.AI.GenerateReport(title, report.Outline(entries), *format)
+		if err == nil {
+			fmt.Println(summary)
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  AI summary failed, listing commits instead: %v\n", err)
+	}
+
+	fmt.Print(report.Fallback(title, entries, *format))
+	return nil
+}
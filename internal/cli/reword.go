@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/git"
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+	"github.com/Shubhpreet-Rana/codegenius/internal/scope"
+)
+
+// Reword regenerates the message of an existing commit (or every commit in a
+// range) from its diff and rewrites history with the new message.
+//
+//	codegenius reword [--yes] [--context "..."] [<sha>|HEAD|<base>..<tip>]
+func Reword(service *interfaces.Service, args []string) error {
+	flags := flag.NewFlagSet("reword", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "Accept generated messages without prompting")
+	context := flags.String("context", "", "Additional context for the AI")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	target := "HEAD"
+	if flags.NArg() > 0 {
+		target = flags.Arg(0)
+	}
+
+	commits, err := resolveRewordTargets(service.Git, target)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		fmt.Println("⚠️  No commits to reword.")
+		return nil
+	}
+
+	branchName, err := service.Git.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("error getting current branch: %v", err)
+	}
+
+	messages := make(map[string]string)
+	for _, sha := range commits {
+		message, err := rewordMessage(service, sha, branchName, *context, *yes)
+		if err != nil {
+			return err
+		}
+		if message != "" {
+			messages[sha] = message
+		}
+	}
+
+	if len(messages) == 0 {
+		fmt.Println("🚫 Nothing to reword.")
+		return nil
+	}
+
+	fmt.Printf("✏️  Rewording %d commit(s)...\n", len(messages))
+	opts := git.ResolveCommitOptions(service.Config.GetCommit(), interfaces.CommitOptions{})
+	if err := service.Git.RewordCommits(messages, opts); err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Commit messages rewritten successfully!")
+	return nil
+}
+
+// resolveRewordTargets expands a single revision or a range into full SHAs
+func resolveRewordTargets(repo interfaces.CommitReader, target string) ([]string, error) {
+	if strings.Contains(target, "..") {
+		return repo.ListCommits(target)
+	}
+
+	sha, err := repo.ResolveRevision(target)
+	if err != nil {
+		return nil, err
+	}
+	return []string{sha}, nil
+}
+
+// rewordMessage generates a replacement message for one commit and lets the
+// user accept, edit or skip it. An empty result means the commit is skipped.
+func rewordMessage(service *interfaces.Service, sha, branchName, additionalContext string, autoAccept bool) (string, error) {
+	original, err := service.Git.GetCommitMessage(sha)
+	if err != nil {
+		return "", err
+	}
+
+	diff, err := service.Git.GetCommitDiff(sha)
+	if err != nil {
+		return "", err
+	}
+
+	context := fmt.Sprintf("This commit is being reworded. Its original message was: %q", original)
+	if additionalContext != "" {
+		context += "\n" + additionalContext
+	}
+
+	fmt.Printf("\n🧠 Generating message for %s...\n", sha[:7])
+	commitScope, _ := scope.ForChanges(service.Git, service.Config.GetProject(), diff.Paths())
+	message, err := service.AI.GenerateCommitMessage(interfaces.CommitRequest{
+		Diff:    diff,
+		Branch:  branchName,
+		Context: context,
+		Scope:   commitScope,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error generating commit message for %s: %v", sha[:7], err)
+	}
+
+	fmt.Printf("📜 Original:\n%s\n\n📝 Generated:\n%s\n\n", original, message)
+	if autoAccept {
+		return message, nil
+	}
+
+	switch strings.ToLower(prompt("Use this message? (y/n/e for edit): ")) {
+	case "y", "yes":
+		return message, nil
+	case "e", "edit":
+		return service.Git.EditCommitMessage(message)
+	default:
+		fmt.Printf("⏭️  Keeping original message for %s\n", sha[:7])
+		return "", nil
+	}
+}
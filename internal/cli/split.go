@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/git"
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// Split breaks the staged diff into several logical commits proposed by the AI.
+// Use the TUI to adjust the grouping before committing.
+//
+//	codegenius split [--yes]
+func Split(service *interfaces.Service, args []string) error {
+	flags := flag.NewFlagSet("split", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "Create the proposed commits without prompting")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	patch, err := service.Git.GetStagedPatch()
+	if err != nil {
+		return err
+	}
+
+	plan := git.NewSplitPlan(patch)
+	if len(plan.Chunks) < 2 {
+		fmt.Println("⚠️  Not enough staged changes to split. Use the regular commit flow instead.")
+		return nil
+	}
+
+	branchName, err := service.Git.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("error getting current branch: %v", err)
+	}
+
+	fmt.Printf("🧠 Grouping %d chunks into logical commits...\n", len(plan.Chunks))
+	groups, err := service.AI.ProposeCommitGroups(plan.Descriptions, branchName)
+	if err != nil {
+		return fmt.Errorf("error proposing commit groups: %v", err)
+	}
+	groups = git.AssignRemaining(git.NormalizeGroups(groups, len(plan.Chunks)), len(plan.Chunks))
+
+	for i, group := range groups {
+		fmt.Printf("\n📦 Commit %d: %s\n", i+1, group.Message)
+		for _, chunk := range group.Chunks {
+			fmt.Printf("   • [%d] %s\n", chunk, plan.Label(chunk))
+		}
+	}
+	fmt.Println()
+
+	if !*yes && !confirm(fmt.Sprintf("Create these %d commits?", len(groups))) {
+		fmt.Println("🚫 Split cancelled. Run `codegenius --tui` to adjust the grouping.")
+		return nil
+	}
+
+	commits, err := plan.Commits(groups)
+	if err != nil {
+		return err
+	}
+
+	opts := git.ResolveCommitOptions(service.Config.GetCommit(), interfaces.CommitOptions{})
+	if err := service.Git.CommitSeries(commits, opts); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Created %d commits!\n", len(commits))
+	return nil
+}
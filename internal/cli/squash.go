@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/git"
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// Squash synthesizes one commit message for every commit in base..HEAD and,
+// with --apply, squashes them into a single commit after saving a backup ref.
+//
+//	codegenius squash [--base <ref>] [--apply] [--yes]
+func Squash(service *interfaces.Service, args []string) error {
+	flags := flag.NewFlagSet("squash", flag.ContinueOnError)
+	base := flags.String("base", "", "Branch or commit the feature branch started from (default: origin/HEAD, main or master)")
+	apply := flags.Bool("apply", false, "Soft-reset to the merge base and commit with the synthesized message")
+	yes := flags.Bool("yes", false, "Apply without prompting (requires --apply)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *base == "" {
+		defaultBranch, err := service.Git.GetDefaultBranch()
+		if err != nil {
+			return err
+		}
+		*base = defaultBranch
+	}
+
+	branchName, err := service.Git.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("error getting current branch: %v", err)
+	}
+
+	mergeBase, err := service.Git.GetMergeBase(*base, "HEAD")
+	if err != nil {
+		return err
+	}
+
+	commits, err := service.Git.ListCommits(mergeBase + "..HEAD")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		fmt.Printf("⚠️  No commits between %s and HEAD.\n", *base)
+		return nil
+	}
+
+	messages := make([]string, 0, len(commits))
+	for _, sha := range commits {
+		message, err := service.Git.GetCommitMessage(sha)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+
+	diff, err := service.Git.GetRangeDiff(mergeBase, "HEAD")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🧠 Synthesizing a message for %d commit(s) on %s since %s...\n", len(commits), branchName, *base)
+	message, err := service.AI.GenerateSquashMessage(messages, diff, branchName)
+	if err != nil {
+		return fmt.Errorf("error generating squash message: %v", err)
+	}
+
+	fmt.Printf("\n📝 Squash message:\n%s\n\n", message)
+
+	if !*apply {
+		fmt.Println("💡 Re-run with --apply to squash the commits with this message.")
+		return nil
+	}
+
+	if !*yes {
+		switch strings.ToLower(prompt("Squash with this message? (y/n/e for edit): ")) {
+		case "y", "yes":
+		case "e", "edit":
+			message, err = service.Git.EditCommitMessage(message)
+			if err != nil {
+				return fmt.Errorf("error editing commit message: %v", err)
+			}
+		default:
+			fmt.Println("🚫 Squash cancelled.")
+			return nil
+		}
+	}
+
+	return applySquash(service, mergeBase, branchName, message)
+}
+
+// applySquash collapses mergeBase..HEAD into a single commit, restoring the
+// original branch tip if the commit fails
+func applySquash(service *interfaces.Service, mergeBase, branchName, message string) error {
+	hasStaged, err := service.Git.HasStagedChanges()
+	if err != nil {
+		return fmt.Errorf("error checking staged changes: %v", err)
+	}
+	if hasStaged {
+		return fmt.Errorf("staged changes would be folded into the squash commit; commit or unstage them first")
+	}
+
+	backup, err := service.Git.CreateBackupRef(branchName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("💾 Backup saved as %s\n", backup)
+
+	if err := service.Git.ResetSoft(mergeBase); err != nil {
+		return err
+	}
+
+	opts := git.ResolveCommitOptions(service.Config.GetCommit(), interfaces.CommitOptions{})
+	if err := service.Git.CommitWithMessage(message, opts); err != nil {
+		if restoreErr := service.Git.ResetSoft(backup); restoreErr != nil {
+			return fmt.Errorf("squash commit failed (%v) and restoring %s failed: %v", err, backup, restoreErr)
+		}
+		return fmt.Errorf("squash commit failed, branch restored from %s: %v", backup, err)
+	}
+
+	fmt.Println("✅ Commits squashed successfully!")
+	fmt.Printf("💡 To undo: git reset --hard %s\n", backup)
+	return nil
+}
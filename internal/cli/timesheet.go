@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+	"github.com/Shubhpreet-Rana/codegenius/internal/report"
+)
+
+// Timesheet estimates hours worked per day, repository and scope from commit
+// timestamps and writes them as CSV or Markdown. Without a period it covers
+// the current month.
+//
+//	codegenius timesheet [--format csv|md] [--output <file>] [--gap 2h] [--lead-in 30m] [history filters]
+func Timesheet(service *interfaces.Service, args []string) error {
+	historyConfig := service.Config.GetHistory()
+
+	flags := flag.NewFlagSet("timesheet", flag.ContinueOnError)
+	format := flags.String("format", "md", "Output format: csv or md")
+	output := flags.String("output", "", "Write to this file instead of stdout")
+	gap := flags.Duration("gap", time.Duration(historyConfig.SessionGapMinutes)*time.Minute, "A longer pause between commits starts a new session")
+	leadIn := flags.Duration("lead-in", time.Duration(historyConfig.LeadInMinutes)*time.Minute, "Time credited before the first commit of a session")
+	q, week := queryFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := resolveWeek(q, *week); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "md" {
+		return fmt.Errorf("unknown format %q (available: csv, md)", *format)
+	}
+	if *gap <= 0 || *leadIn < 0 {
+		return fmt.Errorf("--gap must be positive and --lead-in must not be negative")
+	}
+	if q.Since == "" && q.Until == "" && q.Period == "" {
+		q.Since = "this month"
+	}
+
+	// Timesheets cover every repository when the central store is enabled
+	resolveAllRepos(flags, q, historyConfig)
+
+	if err := service.History.Load(); err != nil {
+		return fmt.Errorf("failed to load work history: %v", err)
+	}
+	entries, err := service.History.Query(*q)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, "💡 No commits found for this period.")
+		return nil
+	}
+
+	sheet := report.BuildTimesheet(entries, report.TimesheetOptions{Gap: *gap, LeadIn: *leadIn})
+
+	var rendered bytes.Buffer
+	if *format == "csv" {
+		if err := sheet.WriteCSV(&rendered); err != nil {
+			return fmt.Errorf("error writing timesheet: %v", err)
+		}
+	} else {
+		rendered.WriteString(sheet.Markdown(timesheetTitle(*q)))
+	}
+
+	if *output == "" {
+		fmt.Print(rendered.String())
+		return nil
+	}
+	if err := os.WriteFile(*output, rendered.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", *output, err)
+	}
+	fmt.Printf("✅ Timesheet written to %s (%.2f hours, %d commits)\n", *output, sheet.Total.Hours(), len(entries))
+	return nil
+}
+
+// timesheetTitle names the period a timesheet covers
+func timesheetTitle(q interfaces.HistoryQuery) string {
+	switch {
+	case q.Period != "":
+		return "Timesheet — " + q.Period
+	case q.Since == "":
+		return "Timesheet — until " + q.Until
+	case q.Until != "":
+		return fmt.Sprintf("Timesheet — %s to %s", q.Since, q.Until)
+	default:
+		return "Timesheet — since " + q.Since
+	}
+}
@@ -3,7 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
-	"strings"
+	"path/filepath"
 
 	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
 	"gopkg.in/yaml.v2"
@@ -13,14 +13,20 @@ const configFile = ".codegenius.yaml"
 
 // Config represents the main configuration structure
 type Config struct {
+	Version int                      `yaml:"version"`
 	Project interfaces.ProjectConfig `yaml:"project"`
 	AI      interfaces.AIConfig      `yaml:"ai"`
 	Review  interfaces.ReviewConfig  `yaml:"review"`
+	Commit  interfaces.CommitConfig  `yaml:"commit"`
+	History interfaces.HistoryConfig `yaml:"history"`
 }
 
 // Manager implements the ConfigManager interface
 type Manager struct {
-	config *Config
+	config    *Config
+	root      string            // repository root holding the project config file
+	layers    []layer           // sources merged into config, lowest precedence first
+	overrides map[string][]byte // nested config files by directory, nil when absent
 }
 
 // NewManager creates a new configuration manager
@@ -28,26 +34,28 @@ func NewManager() *Manager {
 	return &Manager{}
 }
 
-// Load reads and parses the configuration file
+// Load builds the configuration from its layers, each overriding the one
+// before: built-in defaults, the global file (GlobalConfigPath), the
+// .codegenius.yaml at the repository root, and CODEGENIUS_* environment
+// variables. Nested directory overrides are applied per file by GroupByConfig.
 func (m *Manager) Load() error {
-	config := getDefaultConfig()
-
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		m.config = config
-		return nil // Return default config
-	}
+	m.root = findRepoRoot()
+	m.overrides = nil
 
-	file, err := os.ReadFile(configFile)
+	layers, err := m.readLayers()
 	if err != nil {
-		return fmt.Errorf("error reading config file: %v", err)
+		return err
 	}
 
-	err = yaml.Unmarshal(file, config)
-	if err != nil {
-		return fmt.Errorf("error parsing config file: %v", err)
+	config := getDefaultConfig()
+	for _, l := range layers {
+		if err := yaml.Unmarshal(l.data, config); err != nil {
+			return fmt.Errorf("error parsing %s: %v", l.origin, err)
+		}
 	}
 
 	m.config = config
+	m.layers = layers
 	return nil
 }
 
@@ -62,7 +70,7 @@ func (m *Manager) Save() error {
 		return fmt.Errorf("error marshaling config: %v", err)
 	}
 
-	err = os.WriteFile(configFile, data, 0644)
+	err = os.WriteFile(m.repoConfigPath(), data, 0644)
 	if err != nil {
 		return fmt.Errorf("error writing config file: %v", err)
 	}
@@ -70,32 +78,13 @@ func (m *Manager) Save() error {
 	return nil
 }
 
-// Initialize sets up the configuration with user input
-func (m *Manager) Initialize() error {
-	if m.config == nil {
-		m.config = getDefaultConfig()
-	}
-
-	// Set basic project details
-	m.config.Project.Language = detectProjectLanguage()
-
-	// Save the configuration
-	return m.Save()
-}
-
 // ShouldIgnoreFile checks if a file should be ignored based on patterns
 func (m *Manager) ShouldIgnoreFile(filename string) bool {
 	if m.config == nil {
 		return false
 	}
 
-	for _, pattern := range m.config.Project.IgnoreFiles {
-		if strings.Contains(filename, pattern) ||
-			strings.HasSuffix(filename, strings.TrimPrefix(pattern, "*")) {
-			return true
-		}
-	}
-	return false
+	return interfaces.MatchesIgnorePattern(m.config.Project.IgnoreFiles, filename)
 }
 
 // GetProject returns the project configuration
@@ -122,6 +111,22 @@ func (m *Manager) GetReview() interfaces.ReviewConfig {
 	return m.config.Review
 }
 
+// GetCommit returns the commit configuration
+func (m *Manager) GetCommit() interfaces.CommitConfig {
+	if m.config == nil {
+		return interfaces.CommitConfig{}
+	}
+	return m.config.Commit
+}
+
+// GetHistory returns the work history configuration
+func (m *Manager) GetHistory() interfaces.HistoryConfig {
+	if m.config == nil {
+		return interfaces.HistoryConfig{}
+	}
+	return m.config.History
+}
+
 // GetConfig returns the full configuration (for backward compatibility)
 func (m *Manager) GetConfig() *Config {
 	return m.config
@@ -151,22 +156,34 @@ func (m *Manager) SetReview(review interfaces.ReviewConfig) {
 	m.config.Review = review
 }
 
+// SetCommit updates the commit configuration
+func (m *Manager) SetCommit(commit interfaces.CommitConfig) {
+	if m.config == nil {
+		m.config = getDefaultConfig()
+	}
+	m.config.Commit = commit
+}
+
 // getDefaultConfig returns a configuration with sensible defaults
 func getDefaultConfig() *Config {
 	return &Config{
+		Version: CurrentVersion,
 		Project: interfaces.ProjectConfig{
-			Name:     "My Project",
-			Language: "go",
-			Overview: "A Go project using CodeGenius for intelligent commits and reviews",
+			Name:      "My Project",
+			Languages: []string{"go"},
+			Overview:  "A Go project using CodeGenius for intelligent commits and reviews",
 			Scopes: []string{
 				"core", "api", "docs", "deps", "scripts", "ci", "build",
 			},
-			Standards:   "https://golang.org/doc/effective_go.html",
-			IgnoreFiles: []string{"go.mod", "go.sum", "*.lock", "node_modules/", ".git/"},
+			ScopeMap:     []interfaces.ScopeRule{},
+			DetectScopes: true,
+			Standards:    "https://golang.org/doc/effective_go.html",
+			IgnoreFiles:  []string{"go.mod", "go.sum", "*.lock", "node_modules/", ".git/"},
 		},
 		AI: interfaces.AIConfig{
-			Model:     "gemini-2.0-flash",
-			MaxTokens: 4000,
+			Model:            "gemini-2.0-flash",
+			MaxTokens:        4000,
+			MaxFileDiffBytes: 20000,
 			ContextTemplates: map[string]string{
 				"default": "This is a standard commit message generation request.",
 				"bugfix":  "Focus on describing the bug that was fixed and its impact.",
@@ -183,11 +200,21 @@ func getDefaultConfig() *Config {
 			},
 			CustomRules: map[string]string{},
 		},
+		Commit: interfaces.CommitConfig{
+			Signoff:  false,
+			Team:     []string{},
+			Trailers: []interfaces.Trailer{},
+		},
+		History: interfaces.HistoryConfig{
+			SessionGapMinutes: 120,
+			LeadInMinutes:     30,
+		},
 	}
 }
 
-// detectProjectLanguage attempts to detect the project language based on files
-func detectProjectLanguage() string {
+// detectProjectLanguage attempts to detect the project language from manifest
+// files at the root, for when the files cannot be listed with git
+func detectProjectLanguage(root string) string {
 	files := []struct {
 		pattern  string
 		language string
@@ -201,7 +228,7 @@ func detectProjectLanguage() string {
 	}
 
 	for _, file := range files {
-		if _, err := os.Stat(file.pattern); err == nil {
+		if _, err := os.Stat(filepath.Join(root, file.pattern)); err == nil {
 			return file.language
 		}
 	}
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// commitScopeRegex captures the scope of a conventional commit subject
+var commitScopeRegex = regexp.MustCompile(`^\w+\(([^)]+)\)!?:`)
+
+// maxSuggestedScopes caps how many scopes the wizard proposes
+const maxSuggestedScopes = 15
+
+// extensionLanguages maps source file extensions to languages
+var extensionLanguages = map[string]string{
+	".go": "go", ".js": "javascript", ".jsx": "javascript", ".mjs": "javascript",
+	".ts": "typescript", ".tsx": "typescript", ".py": "python", ".rs": "rust",
+	".java": "java", ".kt": "kotlin", ".rb": "ruby", ".php": "php", ".cs": "csharp",
+	".swift": "swift", ".c": "c", ".h": "c", ".cpp": "cpp", ".cc": "cpp", ".hpp": "cpp",
+	".scala": "scala", ".dart": "dart", ".ex": "elixir", ".exs": "elixir",
+}
+
+// languageIgnores are the ignore patterns suggested for each language
+var languageIgnores = map[string][]string{
+	"go":         {"go.sum", "vendor/", "*.pb.go"},
+	"javascript": {"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "node_modules/", "dist/", "*.min.js"},
+	"typescript": {"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "node_modules/", "dist/", "*.d.ts"},
+	"python":     {"poetry.lock", "__pycache__/", "*.pyc", ".venv/"},
+	"rust":       {"Cargo.lock", "target/"},
+	"java":       {"target/", "build/", "*.class"},
+	"kotlin":     {"build/", "*.class"},
+	"ruby":       {"Gemfile.lock", "vendor/bundle/"},
+	"php":        {"composer.lock", "vendor/"},
+	"csharp":     {"bin/", "obj/"},
+}
+
+// containerDirs hold packages rather than being a scope themselves, so their
+// children are suggested as scopes instead
+var containerDirs = map[string]bool{
+	"internal": true, "pkg": true, "src": true, "packages": true, "services": true,
+	"apps": true, "libs": true, "modules": true, "crates": true, "cmd": true,
+}
+
+// Suggest inspects the repository and proposes project settings: the name
+// from the remote, languages from tracked files, scopes from the directory
+// layout and recent commit subjects, and matching ignore patterns
+func (m *Manager) Suggest() interfaces.InitOptions {
+	if m.root == "" {
+		m.root = findRepoRoot()
+	}
+
+	files := m.trackedFiles()
+	languages := detectLanguages(files)
+	if len(languages) == 0 {
+		if language := detectProjectLanguage(m.root); language != "unknown" {
+			languages = []string{language}
+		}
+	}
+
+	overview := "A project using CodeGenius for intelligent commits and reviews"
+	if len(languages) > 0 {
+		overview = fmt.Sprintf("A %s project using CodeGenius for intelligent commits and reviews", strings.Join(languages, "/"))
+	}
+
+	return interfaces.InitOptions{
+		Name:        m.detectName(),
+		Overview:    overview,
+		Languages:   languages,
+		Scopes:      mergeScopes(m.commitScopes(), directoryScopes(files)),
+		IgnoreFiles: suggestIgnores(languages),
+		ReviewTypes: append([]string{}, KnownReviewTypes...),
+	}
+}
+
+// Initialize writes the detected suggestions without asking any questions
+func (m *Manager) Initialize() error {
+	return m.InitializeWith(m.Suggest())
+}
+
+// InitializeWith writes the chosen project settings to the repository config.
+// A new file starts from the defaults; an existing one keeps its other
+// settings and comments.
+func (m *Manager) InitializeWith(options interfaces.InitOptions) error {
+	path := m.FilePath(false)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		data, err := yamlv3.Marshal(getDefaultConfig())
+		if err != nil {
+			return fmt.Errorf("error marshaling config: %v", err)
+		}
+		if err := WriteFile(path, data); err != nil {
+			return err
+		}
+	}
+
+	err := editFile(path, func(document *yamlv3.Node) error {
+		// An existing file from an older release is upgraded before editing
+		if _, err := upgradeDocument(document); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		setNode(document, []string{"project", "name"}, stringNode(options.Name))
+		setNode(document, []string{"project", "overview"}, stringNode(options.Overview))
+		setNode(document, []string{"project", "languages"}, stringSequence(options.Languages))
+		setNode(document, []string{"project", "scopes"}, stringSequence(options.Scopes))
+		setNode(document, []string{"project", "ignore_files"}, stringSequence(options.IgnoreFiles))
+		setNode(document, []string{"review", "enabled_types"}, stringSequence(options.ReviewTypes))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.Load()
+}
+
+// stringNode builds a YAML string scalar
+func stringNode(value string) *yamlv3.Node {
+	return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// trackedFiles lists the repository's tracked and untracked-but-not-ignored files
+func (m *Manager) trackedFiles() []string {
+	cmd := exec.Command("git", "ls-files", "--cached", "--others", "--exclude-standard")
+	cmd.Dir = m.root
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, file := range strings.Split(string(output), "\n") {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// detectName derives the project name from the origin remote (or the first
+// remote), falling back to the repository directory name
+func (m *Manager) detectName() string {
+	remote := "origin"
+	remotes := exec.Command("git", "remote")
+	remotes.Dir = m.root
+	if output, err := remotes.Output(); err == nil {
+		names := strings.Fields(string(output))
+		if len(names) > 0 && !containsName(names, remote) {
+			remote = names[0]
+		}
+	}
+
+	cmd := exec.Command("git", "remote", "get-url", remote)
+	cmd.Dir = m.root
+	if output, err := cmd.Output(); err == nil {
+		if name := repoNameFromURL(strings.TrimSpace(string(output))); name != "" {
+			return name
+		}
+	}
+
+	if absolute, err := filepath.Abs(m.root); err == nil {
+		return filepath.Base(absolute)
+	}
+	return "My Project"
+}
+
+// repoNameFromURL extracts "repo" from URLs such as git@host:org/repo.git or
+// https://host/org/repo
+func repoNameFromURL(url string) string {
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	if index := strings.LastIndexAny(url, "/:"); index != -1 {
+		url = url[index+1:]
+	}
+	return url
+}
+
+// detectLanguages ranks languages by file count, keeping those with at least
+// 5% of the source files (and always the most common one)
+func detectLanguages(files []string) []string {
+	counts := make(map[string]int)
+	total := 0
+	for _, file := range files {
+		if language, ok := extensionLanguages[strings.ToLower(path.Ext(file))]; ok {
+			counts[language]++
+			total++
+		}
+	}
+
+	languages := make([]string, 0, len(counts))
+	for language := range counts {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if counts[languages[i]] != counts[languages[j]] {
+			return counts[languages[i]] > counts[languages[j]]
+		}
+		return languages[i] < languages[j]
+	})
+
+	var significant []string
+	for i, language := range languages {
+		if i == 0 || counts[language]*20 >= total {
+			significant = append(significant, language)
+		}
+	}
+	return significant
+}
+
+// directoryScopes proposes scopes from top-level directories, descending one
+// level into package containers such as internal/ or packages/
+func directoryScopes(files []string) []string {
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, file := range files {
+		parts := strings.Split(file, "/")
+		if len(parts) < 2 || strings.HasPrefix(parts[0], ".") || isBuildDir(parts[0]) {
+			continue
+		}
+
+		scope := parts[0]
+		if containerDirs[scope] {
+			if len(parts) < 3 {
+				continue
+			}
+			scope = parts[1]
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// isBuildDir reports whether a top-level directory holds build output or dependencies
+func isBuildDir(dir string) bool {
+	switch dir {
+	case "node_modules", "vendor", "dist", "build", "target", "bin", "obj":
+		return true
+	}
+	return false
+}
+
+// commitScopes returns the scopes used in recent conventional commit
+// subjects, most frequent first
+func (m *Manager) commitScopes() []string {
+	cmd := exec.Command("git", "log", "-200", "--format=%s")
+	cmd.Dir = m.root
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, subject := range strings.Split(string(output), "\n") {
+		if match := commitScopeRegex.FindStringSubmatch(subject); match != nil {
+			for _, scope := range strings.Split(match[1], ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					counts[scope]++
+				}
+			}
+		}
+	}
+
+	scopes := make([]string, 0, len(counts))
+	for scope := range counts {
+		scopes = append(scopes, scope)
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		if counts[scopes[i]] != counts[scopes[j]] {
+			return counts[scopes[i]] > counts[scopes[j]]
+		}
+		return scopes[i] < scopes[j]
+	})
+	return scopes
+}
+
+// mergeScopes combines scope lists in priority order without duplicates
+func mergeScopes(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	merged := make([]string, 0)
+	for _, list := range lists {
+		for _, scope := range list {
+			if len(merged) >= maxSuggestedScopes {
+				return merged
+			}
+			if !seen[scope] {
+				seen[scope] = true
+				merged = append(merged, scope)
+			}
+		}
+	}
+	return merged
+}
+
+// suggestIgnores proposes ignore patterns for the detected languages
+func suggestIgnores(languages []string) []string {
+	seen := map[string]bool{".git/": true}
+	ignores := []string{".git/"}
+	for _, language := range languages {
+		for _, pattern := range languageIgnores[language] {
+			if !seen[pattern] {
+				seen[pattern] = true
+				ignores = append(ignores, pattern)
+			}
+		}
+	}
+	return ignores
+}
+
+// containsName reports whether names contains name
+func containsName(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,364 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// FilePath returns the file that `config set` and friends modify: the global
+// user config, or the .codegenius.yaml at the repository root
+func (m *Manager) FilePath(global bool) string {
+	if global {
+		return GlobalConfigPath()
+	}
+	if m.root == "" {
+		m.root = findRepoRoot()
+	}
+	return m.repoConfigPath()
+}
+
+// Get returns the effective value of a dotted key. A section such as "ai"
+// returns every setting beneath it.
+func (m *Manager) Get(key string) ([]string, error) {
+	if _, err := keyType(key); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, value := range m.Describe() {
+		switch {
+		case value.Key == key:
+			return []string{value.Value}, nil
+		case strings.HasPrefix(value.Key, key+"."):
+			values = append(values, fmt.Sprintf("%s = %s", value.Key, value.Value))
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s is not set", key)
+	}
+	return values, nil
+}
+
+// SetValue sets a dotted key in a config file. Lists take a comma-separated
+// value. Comments and key order in the file are preserved.
+func SetValue(path, key, raw string) error {
+	valueType, err := keyType(key)
+	if err != nil {
+		return err
+	}
+
+	var value *yamlv3.Node
+	switch {
+	case valueType.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.String:
+		items, _ := parseEnvValue(raw, reflect.Slice)
+		value = stringSequence(items.([]string))
+	case isScalar(valueType.Kind()):
+		if value, err = scalarNode(raw, valueType.Kind()); err != nil {
+			return fmt.Errorf("invalid value for %s: %v", key, err)
+		}
+	default:
+		return fmt.Errorf("%s cannot be set from the command line, use 'codegenius config edit'", key)
+	}
+
+	return editFile(path, func(document *yamlv3.Node) error {
+		setNode(document, strings.Split(key, "."), value)
+		return nil
+	})
+}
+
+// AddValue appends a value to a list key in a config file. When the file does
+// not set the list yet, it is seeded with the inherited value so the addition
+// extends rather than replaces it. Adding a value already present is a no-op.
+func AddValue(path, key, value string, inherited []string) error {
+	valueType, err := keyType(key)
+	if err != nil {
+		return err
+	}
+	if valueType.Kind() != reflect.Slice || valueType.Elem().Kind() != reflect.String {
+		return fmt.Errorf("%s is not a list", key)
+	}
+
+	return editFile(path, func(document *yamlv3.Node) error {
+		list := findNode(document, strings.Split(key, "."))
+		if list == nil || list.Kind != yamlv3.SequenceNode {
+			list = stringSequence(inherited)
+			setNode(document, strings.Split(key, "."), list)
+		}
+		for _, item := range list.Content {
+			if item.Value == value {
+				return nil
+			}
+		}
+		list.Content = append(list.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value})
+		return nil
+	})
+}
+
+// ValidateFile checks the contents of a single config file
+func ValidateFile(path string) []Issue {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return []Issue{{File: path, Message: err.Error()}}
+	}
+	return validateDocument(path, data)
+}
+
+// ListValues returns the effective items of a list key, for seeding AddValue
+func (m *Manager) ListValues(key string) []string {
+	items := []string{}
+	if m.config == nil {
+		return items
+	}
+
+	data, err := yamlv3.Marshal(m.config)
+	if err != nil {
+		return items
+	}
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return items
+	}
+
+	if list := findNode(root.Content[0], strings.Split(key, ".")); list != nil && list.Kind == yamlv3.SequenceNode {
+		for _, item := range list.Content {
+			items = append(items, item.Value)
+		}
+	}
+	return items
+}
+
+// editFile applies an edit to the YAML document in path (a new document
+// when the file does not exist), validates the result and writes it back
+func editFile(path string, edit func(document *yamlv3.Node) error) error {
+	// A new file is written in the current format
+	document := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+	setVersion(document, CurrentVersion)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading %s: %v", path, err)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		var root yamlv3.Node
+		if err := yamlv3.Unmarshal(data, &root); err != nil {
+			return fmt.Errorf("error parsing %s: %v", path, err)
+		}
+		if len(root.Content) == 0 || root.Content[0].Kind != yamlv3.MappingNode {
+			return fmt.Errorf("%s does not contain a mapping at the top level", path)
+		}
+		document = root.Content[0]
+	}
+
+	if err := edit(document); err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+	encoder := yamlv3.NewEncoder(&output)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("error encoding %s: %v", path, err)
+	}
+	encoder.Close()
+
+	// Only problems introduced by this edit block the write; existing ones are
+	// left for `config validate` to report
+	existing := make(map[string]bool)
+	for _, issue := range validateDocument(path, data) {
+		existing[issue.Message] = true
+	}
+	for _, issue := range validateDocument(path, output.Bytes()) {
+		if !issue.Warning && !existing[issue.Message] {
+			return fmt.Errorf("refusing to write invalid configuration: %s", issue)
+		}
+	}
+
+	return WriteFile(path, output.Bytes())
+}
+
+// WriteFile replaces a config file with data via a temporary file and rename,
+// creating its directory if needed
+func WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating %s: %v", filepath.Dir(path), err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".codegenius-*.yaml")
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return nil
+}
+
+// keyType returns the Go type stored at a dotted key, e.g. string for
+// "ai.model" or "ai.context_templates.bugfix"
+func keyType(key string) (reflect.Type, error) {
+	current := reflect.TypeOf(Config{})
+	for _, segment := range strings.Split(key, ".") {
+		switch current.Kind() {
+		case reflect.Struct:
+			field, ok := structField(current, segment)
+			if !ok {
+				if suggestion := closestName(segment, fieldNames(current)); suggestion != "" {
+					return nil, fmt.Errorf("unknown config key %q (did you mean %q?)", key, suggestion)
+				}
+				return nil, fmt.Errorf("unknown config key %q", key)
+			}
+			current = field
+		case reflect.Map:
+			current = current.Elem()
+		default:
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+	}
+	return current, nil
+}
+
+// structField finds the field of a struct type with the given YAML name
+func structField(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if yamlName(t.Field(i)) == name {
+			return t.Field(i).Type, true
+		}
+	}
+	return nil, false
+}
+
+// fieldNames lists the YAML names of a struct type's fields
+func fieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := yamlName(t.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// isScalar reports whether a kind is stored as a single YAML scalar
+func isScalar(kind reflect.Kind) bool {
+	return kind == reflect.String || kind == reflect.Int || kind == reflect.Bool
+}
+
+// scalarNode builds a YAML scalar of the right type from a command-line value
+func scalarNode(raw string, kind reflect.Kind) (*yamlv3.Node, error) {
+	switch kind {
+	case reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected a whole number, got %q", raw)
+		}
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: strconv.Itoa(number)}, nil
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", raw)
+		}
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value)}, nil
+	default:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: raw}, nil
+	}
+}
+
+// stringSequence builds a YAML list of strings
+func stringSequence(items []string) *yamlv3.Node {
+	list := &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+	for _, item := range items {
+		list.Content = append(list.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: item})
+	}
+	return list
+}
+
+// findNode returns the value at a key path in a mapping, or nil
+func findNode(mapping *yamlv3.Node, path []string) *yamlv3.Node {
+	current := mapping
+	for _, segment := range path {
+		if current.Kind != yamlv3.MappingNode {
+			return nil
+		}
+		var next *yamlv3.Node
+		for i := 0; i+1 < len(current.Content); i += 2 {
+			if current.Content[i].Value == segment {
+				next = current.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// setNode stores value at a key path, creating missing mappings. An existing
+// value keeps its position and comments; new keys are appended.
+func setNode(mapping *yamlv3.Node, path []string, value *yamlv3.Node) {
+	current := mapping
+	for depth, segment := range path {
+		last := depth == len(path)-1
+
+		var existing *yamlv3.Node
+		for i := 0; i+1 < len(current.Content); i += 2 {
+			if current.Content[i].Value == segment {
+				existing = current.Content[i+1]
+				if last {
+					value.HeadComment = existing.HeadComment
+					value.LineComment = existing.LineComment
+					value.FootComment = existing.FootComment
+					current.Content[i+1] = value
+					return
+				}
+				break
+			}
+		}
+
+		if existing == nil || existing.Kind != yamlv3.MappingNode {
+			next := value
+			if !last {
+				next = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+			}
+			keyNode := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: segment}
+			if existing != nil {
+				// Replace a non-mapping (e.g. an empty value) with the new section
+				for i := 0; i+1 < len(current.Content); i += 2 {
+					if current.Content[i].Value == segment {
+						current.Content[i+1] = next
+					}
+				}
+			} else {
+				current.Content = append(current.Content, keyNode, next)
+			}
+			if last {
+				return
+			}
+			existing = next
+		}
+		current = existing
+	}
+}
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// envPrefix starts every environment override, e.g. CODEGENIUS_AI_MODEL
+	envPrefix = "CODEGENIUS_"
+
+	// OriginDefault marks values that come from the built-in defaults
+	OriginDefault = "default"
+	// OriginEnv marks values that come from CODEGENIUS_* environment variables
+	OriginEnv = "environment"
+)
+
+// layer is one source of configuration, applied over the layers before it
+type layer struct {
+	origin string // file path, or OriginEnv
+	data   []byte
+}
+
+// envSetting is a configuration key that can be set from the environment
+type envSetting struct {
+	section string
+	key     string
+	kind    reflect.Kind // String, Int, Bool or Slice (of strings)
+}
+
+// GlobalConfigPath returns the user-wide config file,
+// $XDG_CONFIG_HOME/codegenius/config.yaml (~/.config when unset)
+func GlobalConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "codegenius", "config.yaml")
+}
+
+// EnvVarName returns the environment variable for a dotted key, e.g.
+// "ai.max_tokens" becomes CODEGENIUS_AI_MAX_TOKENS
+func EnvVarName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// findRepoRoot returns the top-level directory of the enclosing Git
+// repository, falling back to the current directory outside a repository
+func findRepoRoot() string {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return "."
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// readLayers collects the global file, the repository file and the
+// environment, lowest precedence first. Missing files are skipped.
+func (m *Manager) readLayers() ([]layer, error) {
+	var layers []layer
+
+	for _, path := range []string{GlobalConfigPath(), m.repoConfigPath()} {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file %s: %v", path, err)
+		}
+		// Older formats are upgraded in memory; `config migrate` rewrites the file
+		if data, _, err = MigrateDocument(data); err != nil {
+			return nil, fmt.Errorf("error reading config file %s: %v", path, err)
+		}
+		layers = append(layers, layer{origin: path, data: data})
+	}
+
+	env, err := envLayer()
+	if err != nil {
+		return nil, err
+	}
+	if env != nil {
+		layers = append(layers, layer{origin: OriginEnv, data: env})
+	}
+
+	return layers, nil
+}
+
+// repoConfigPath returns the path of the config file at the repository root
+func (m *Manager) repoConfigPath() string {
+	root := m.root
+	if root == "" {
+		root = "."
+	}
+	return filepath.Join(root, configFile)
+}
+
+// envLayer renders CODEGENIUS_<SECTION>_<KEY> variables as a YAML layer, or
+// returns nil when none are set. Lists are comma-separated; maps and lists of
+// objects cannot be set from the environment.
+func envLayer() ([]byte, error) {
+	values := make(map[string]map[string]interface{})
+	for _, setting := range envSettings() {
+		name := EnvVarName(setting.section + "." + setting.key)
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		value, err := parseEnvValue(raw, setting.kind)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", name, err)
+		}
+
+		if values[setting.section] == nil {
+			values[setting.section] = make(map[string]interface{})
+		}
+		values[setting.section][setting.key] = value
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return yaml.Marshal(values)
+}
+
+// envSettings lists every scalar or string-list key of Config
+func envSettings() []envSetting {
+	var settings []envSetting
+
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		section := configType.Field(i)
+		sectionName := yamlName(section)
+		if sectionName == "" || section.Type.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := 0; j < section.Type.NumField(); j++ {
+			field := section.Type.Field(j)
+			key := yamlName(field)
+			if key == "" || isDeprecated(sectionName+"."+key) {
+				continue
+			}
+
+			kind := field.Type.Kind()
+			switch {
+			case kind == reflect.String, kind == reflect.Int, kind == reflect.Bool:
+			case kind == reflect.Slice && field.Type.Elem().Kind() == reflect.String:
+			default:
+				continue
+			}
+			settings = append(settings, envSetting{section: sectionName, key: key, kind: kind})
+		}
+	}
+	return settings
+}
+
+// yamlName returns the YAML key of a struct field, or "" when it is not serialised
+func yamlName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// parseEnvValue converts an environment variable to the type of its key
+func parseEnvValue(raw string, kind reflect.Kind) (interface{}, error) {
+	switch kind {
+	case reflect.Int:
+		return strconv.Atoi(strings.TrimSpace(raw))
+	case reflect.Bool:
+		return strconv.ParseBool(strings.TrimSpace(raw))
+	case reflect.Slice:
+		items := make([]string, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	default:
+		return raw, nil
+	}
+}
+
+// Describe lists every effective setting as a dotted key with its value and
+// the layer it came from
+func (m *Manager) Describe() []interfaces.ConfigValue {
+	if m.config == nil {
+		m.config = getDefaultConfig()
+	}
+
+	values := make(map[string]string)
+	if data, err := yaml.Marshal(m.config); err == nil {
+		values = flattenYAML(data)
+	}
+
+	origins := make(map[string]string, len(values))
+	for key := range values {
+		origins[key] = OriginDefault
+	}
+	for _, l := range m.layers {
+		for key := range flattenYAML(l.data) {
+			if _, ok := values[key]; ok {
+				origins[key] = l.origin
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	described := make([]interfaces.ConfigValue, 0, len(keys))
+	for _, key := range keys {
+		described = append(described, interfaces.ConfigValue{Key: key, Value: values[key], Origin: origins[key]})
+	}
+	return described
+}
+
+// flattenYAML maps the dotted path of every leaf in a YAML document to its
+// rendered value. Lists and empty maps are leaves.
+func flattenYAML(data []byte) map[string]string {
+	var document map[interface{}]interface{}
+	flat := make(map[string]string)
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return flat
+	}
+	flattenInto(flat, "", document)
+	return flat
+}
+
+// flattenInto walks a decoded YAML map, recording leaves under prefix
+func flattenInto(flat map[string]string, prefix string, node map[interface{}]interface{}) {
+	for rawKey, value := range node {
+		key := fmt.Sprint(rawKey)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if child, ok := value.(map[interface{}]interface{}); ok && len(child) > 0 {
+			flattenInto(flat, key, child)
+			continue
+		}
+		flat[key] = renderValue(value)
+	}
+}
+
+// renderValue formats a decoded YAML value on a single line
+func renderValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = renderValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[interface{}]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, fmt.Sprint(key))
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			items[i] = key + ": " + renderValue(v[key])
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
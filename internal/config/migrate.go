@@ -0,0 +1,267 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config format written by this release. Files without
+// a version key predate versioning and are treated as version 1.
+const CurrentVersion = 2
+
+// migration upgrades a config document from one version to the next,
+// reporting whether the document needed any change beyond its version
+type migration struct {
+	from        int
+	description string
+	apply       func(document *yamlv3.Node) bool
+}
+
+// migrations upgrade documents step by step, oldest first
+var migrations = []migration{
+	{from: 1, description: "move project.language into project.languages", apply: migrateLanguages},
+}
+
+// deprecatedKey is a key that still loads but has been replaced
+type deprecatedKey struct {
+	key         string
+	replacement string
+	hint        string
+}
+
+// deprecatedKeys are reported as warnings wherever they appear
+var deprecatedKeys = []deprecatedKey{
+	{key: "project.language", replacement: "project.languages", hint: "the first entry is the primary language"},
+}
+
+// MigrationResult describes what migrating one document changed
+type MigrationResult struct {
+	From    int
+	To      int
+	Applied []string // descriptions of the migrations that changed settings
+}
+
+// MigrateDocument upgrades a config document to CurrentVersion, preserving
+// comments and key order. Documents that are already current are returned
+// unchanged; documents from a newer release are rejected.
+func MigrateDocument(data []byte) ([]byte, MigrationResult, error) {
+	result := MigrationResult{From: CurrentVersion, To: CurrentVersion}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return data, result, nil
+	}
+
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &root); err != nil {
+		return nil, result, err
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yamlv3.MappingNode {
+		return data, result, nil
+	}
+	document := root.Content[0]
+
+	result, err := upgradeDocument(document)
+	if err != nil {
+		return nil, result, err
+	}
+	if result.From == result.To {
+		return data, result, nil
+	}
+
+	var output bytes.Buffer
+	encoder := yamlv3.NewEncoder(&output)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, result, err
+	}
+	encoder.Close()
+	return output.Bytes(), result, nil
+}
+
+// upgradeDocument runs the migrations a parsed document needs, in order
+func upgradeDocument(document *yamlv3.Node) (MigrationResult, error) {
+	result := MigrationResult{To: CurrentVersion}
+
+	version, err := documentVersion(document)
+	if err != nil {
+		return result, err
+	}
+	result.From = version
+	if version > CurrentVersion {
+		return result, fmt.Errorf("config format version %d is newer than this release supports (%d); please upgrade codegenius", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return result, nil
+	}
+
+	for _, step := range migrations {
+		if step.from >= version && step.from < CurrentVersion && step.apply(document) {
+			result.Applied = append(result.Applied, step.description)
+		}
+	}
+	setVersion(document, CurrentVersion)
+	return result, nil
+}
+
+// MigrateFile upgrades a config file on disk, first copying the original to
+// a backup next to it. It returns the backup path, or "" when the file was
+// already current.
+func MigrateFile(path string) (string, MigrationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", MigrationResult{}, fmt.Errorf("error reading %s: %v", path, err)
+	}
+
+	migrated, result, err := MigrateDocument(data)
+	if err != nil {
+		return "", result, fmt.Errorf("%s: %v", path, err)
+	}
+	if result.From == result.To {
+		return "", result, nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, result.From)
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return "", result, fmt.Errorf("error writing backup %s: %v", backup, err)
+	}
+	if err := WriteFile(path, migrated); err != nil {
+		return "", result, err
+	}
+	return backup, result, nil
+}
+
+// documentVersion reads the version key of a document, defaulting to 1
+func documentVersion(document *yamlv3.Node) (int, error) {
+	node := findNode(document, []string{"version"})
+	if node == nil {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(node.Value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid config version %q", node.Value)
+	}
+	return version, nil
+}
+
+// setVersion stores the version as the first key of the document
+func setVersion(document *yamlv3.Node, version int) {
+	value := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if existing := findNode(document, []string{"version"}); existing != nil {
+		existing.Value = value.Value
+		return
+	}
+	key := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "version"}
+	document.Content = append([]*yamlv3.Node{key, value}, document.Content...)
+}
+
+// deleteKey removes a key from a mapping, returning its value
+func deleteKey(mapping *yamlv3.Node, key string) *yamlv3.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
+
+// migrateLanguages (v1 to v2) folds the single project.language into the
+// project.languages list, as its first entry
+func migrateLanguages(document *yamlv3.Node) bool {
+	project := findNode(document, []string{"project"})
+	if project == nil || project.Kind != yamlv3.MappingNode {
+		return false
+	}
+
+	languages := findNode(project, []string{"languages"})
+	if languages == nil {
+		// Rename in place so the key keeps its position and comments
+		for i := 0; i+1 < len(project.Content); i += 2 {
+			key, value := project.Content[i], project.Content[i+1]
+			if key.Value == "language" && value.Kind == yamlv3.ScalarNode && strings.TrimSpace(value.Value) != "" {
+				key.Value = "languages"
+				list := stringSequence([]string{value.Value})
+				list.Style = yamlv3.FlowStyle
+				list.LineComment = value.LineComment
+				project.Content[i+1] = list
+				return true
+			}
+		}
+	}
+
+	language := deleteKey(project, "language")
+	if language == nil {
+		return false
+	}
+	if language.Kind != yamlv3.ScalarNode || strings.TrimSpace(language.Value) == "" || languages == nil || languages.Kind != yamlv3.SequenceNode {
+		return true
+	}
+	for _, item := range languages.Content {
+		if item.Value == language.Value {
+			return true
+		}
+	}
+	languages.Content = append([]*yamlv3.Node{stringNode(language.Value)}, languages.Content...)
+	return true
+}
+
+// isDeprecated reports whether a dotted key has been replaced
+func isDeprecated(key string) bool {
+	for _, deprecated := range deprecatedKeys {
+		if deprecated.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// FileMigration reports the outcome of migrating one config file
+type FileMigration struct {
+	Path   string
+	Backup string // "" when the file was already current
+	MigrationResult
+	Err error
+}
+
+// Migrate upgrades every config file that applies to the repository (global,
+// root and nested overrides) on disk, backing up each file it changes
+func (m *Manager) Migrate() []FileMigration {
+	if m.root == "" {
+		m.root = findRepoRoot()
+	}
+
+	var results []FileMigration
+	for _, path := range m.configFiles() {
+		backup, result, err := MigrateFile(path)
+		results = append(results, FileMigration{Path: path, Backup: backup, MigrationResult: result, Err: err})
+	}
+	return results
+}
+
+// PendingMigrations lists the migrations that would change settings in the
+// config files that apply to the repository. Files that only lack a current
+// version key are not listed: they load the same either way.
+func (m *Manager) PendingMigrations() []string {
+	if m.root == "" {
+		m.root = findRepoRoot()
+	}
+
+	var pending []string
+	for _, path := range m.configFiles() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if _, result, err := MigrateDocument(data); err == nil {
+			for _, applied := range result.Applied {
+				pending = append(pending, fmt.Sprintf("%s: %s", path, applied))
+			}
+		}
+	}
+	return pending
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrateDocument(t *testing.T) {
+	const languagesStep = "move project.language into project.languages"
+
+	tests := []struct {
+		name        string
+		input       string
+		want        string
+		wantFrom    int
+		wantApplied []string
+		wantErr     bool
+	}{
+		{
+			name:        "language becomes a list in place",
+			input:       "project:\n  name: demo\n  language: go # main language\n  overview: x\n",
+			want:        "version: 2\nproject:\n  name: demo\n  languages: [go] # main language\n  overview: x\n",
+			wantFrom:    1,
+			wantApplied: []string{languagesStep},
+		},
+		{
+			name:        "language is prepended to an existing list",
+			input:       "project:\n  language: go\n  languages:\n    - python\n",
+			want:        "version: 2\nproject:\n  languages:\n    - go\n    - python\n",
+			wantFrom:    1,
+			wantApplied: []string{languagesStep},
+		},
+		{
+			name:        "language already listed is dropped",
+			input:       "project:\n  language: go\n  languages: [python, go]\n",
+			want:        "version: 2\nproject:\n  languages: [python, go]\n",
+			wantFrom:    1,
+			wantApplied: []string{languagesStep},
+		},
+		{
+			name:        "empty language is dropped",
+			input:       "project:\n  language: \"\"\n",
+			want:        "version: 2\nproject: {}\n",
+			wantFrom:    1,
+			wantApplied: []string{languagesStep},
+		},
+		{
+			name:     "nothing to migrate besides the version",
+			input:    "commit:\n  max_length: 72\n",
+			want:     "version: 2\ncommit:\n  max_length: 72\n",
+			wantFrom: 1,
+		},
+		{
+			name:        "explicit version 1 keeps comments",
+			input:       "# settings\nversion: 1\nproject:\n  language: go\n",
+			want:        "# settings\nversion: 2\nproject:\n  languages: [go]\n",
+			wantFrom:    1,
+			wantApplied: []string{languagesStep},
+		},
+		{
+			name:     "current document is returned unchanged",
+			input:    "version: 2\nproject:\n    languages:   [go]\n",
+			want:     "version: 2\nproject:\n    languages:   [go]\n",
+			wantFrom: 2,
+		},
+		{
+			name:     "empty document",
+			input:    "",
+			want:     "",
+			wantFrom: 2,
+		},
+		{
+			name:    "newer version",
+			input:   "version: 3\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			input:   "version: two\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, result, err := MigrateDocument([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MigrateDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("MigrateDocument() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if result.From != tt.wantFrom || result.To != CurrentVersion {
+				t.Errorf("MigrateDocument() versions = %d to %d, want %d to %d", result.From, result.To, tt.wantFrom, CurrentVersion)
+			}
+			if !reflect.DeepEqual(result.Applied, tt.wantApplied) {
+				t.Errorf("MigrateDocument() applied = %v, want %v", result.Applied, tt.wantApplied)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+	"gopkg.in/yaml.v2"
+)
+
+// GroupByConfig splits repository-relative files by the nested .codegenius.yaml
+// files that apply to them. Each group carries the root configuration with
+// every override between the root and the files merged on top, the innermost
+// last; environment variables still take precedence. Groups are ordered by directory, root first.
+func (m *Manager) GroupByConfig(files []string) []interfaces.ConfigGroup {
+	if m.config == nil {
+		m.config = getDefaultConfig()
+	}
+
+	groups := make(map[string]*interfaces.ConfigGroup)
+	for _, file := range files {
+		dirs := m.overrideDirs(path.Dir(filepath.ToSlash(file)))
+		key := ""
+		if len(dirs) > 0 {
+			key = dirs[len(dirs)-1]
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			effective := m.effectiveConfig(dirs)
+			group = &interfaces.ConfigGroup{
+				Dir:     key,
+				Files:   []string{},
+				Project: effective.Project,
+				AI:      effective.AI,
+				Review:  effective.Review,
+			}
+			groups[key] = group
+		}
+		group.Files = append(group.Files, file)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]interfaces.ConfigGroup, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *groups[key])
+	}
+	return result
+}
+
+// overrideDirs returns the directories from the root down to dir (exclusive of
+// the root itself) that contain a config file, outermost first
+func (m *Manager) overrideDirs(dir string) []string {
+	if dir == "." || dir == "" {
+		return nil
+	}
+
+	var dirs []string
+	parts := strings.Split(dir, "/")
+	for i := range parts {
+		candidate := strings.Join(parts[:i+1], "/")
+		if _, ok := m.loadOverride(candidate); ok {
+			dirs = append(dirs, candidate)
+		}
+	}
+	return dirs
+}
+
+// loadOverride reads the config file in a directory, caching the result. The
+// second return value is false when the directory has no usable override.
+func (m *Manager) loadOverride(dir string) ([]byte, bool) {
+	if m.overrides == nil {
+		m.overrides = make(map[string][]byte)
+	}
+	if data, ok := m.overrides[dir]; ok {
+		return data, data != nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.root, filepath.FromSlash(dir), configFile))
+	if err == nil {
+		data, _, err = MigrateDocument(data)
+	}
+	if err != nil {
+		if !os.IsNotExist(err) {
+			warnIgnored(dir, err)
+		}
+		data = nil
+	} else if err := yaml.Unmarshal(data, &Config{}); err != nil {
+		warnIgnored(dir, err)
+		data = nil
+	}
+
+	m.overrides[dir] = data
+	return data, data != nil
+}
+
+// effectiveConfig merges the overrides in dirs over a copy of the root
+// configuration. Scalars and lists in an override replace the inherited
+// value; maps are merged key by key.
+func (m *Manager) effectiveConfig(dirs []string) *Config {
+	effective, err := copyConfig(m.config)
+	if err != nil {
+		return m.config
+	}
+
+	for _, dir := range dirs {
+		data, _ := m.loadOverride(dir)
+		if err := yaml.Unmarshal(data, effective); err != nil {
+			warnIgnored(dir, err)
+		}
+	}
+
+	// Environment variables win over every file, including nested ones
+	if len(dirs) > 0 {
+		for _, l := range m.layers {
+			if l.origin == OriginEnv {
+				yaml.Unmarshal(l.data, effective)
+			}
+		}
+	}
+	return effective
+}
+
+// copyConfig deep-copies a configuration through a YAML round trip
+func copyConfig(config *Config) (*Config, error) {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := &Config{}
+	if err := yaml.Unmarshal(data, copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// warnIgnored reports an override that could not be used. It goes to stderr
+// so that commands whose stdout is meant for piping stay clean.
+func warnIgnored(dir string, err error) {
+	fmt.Fprintf(os.Stderr, "⚠️  Ignoring %s: %v\n", filepath.Join(dir, configFile), err)
+}
@@ -0,0 +1,454 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// KnownReviewTypes are the review types the reviewer has prompts for
+var KnownReviewTypes = []string{"security", "performance", "style", "structure"}
+
+// KnownModels are the Gemini models the AI provider can call
+var KnownModels = []string{
+	"gemini-2.5-pro", "gemini-2.5-flash", "gemini-2.5-flash-lite",
+	"gemini-2.0-flash", "gemini-2.0-flash-lite",
+	"gemini-1.5-pro", "gemini-1.5-flash",
+}
+
+// lineRegex extracts the line number from YAML syntax errors
+var lineRegex = regexp.MustCompile(`^line (\d+)`)
+
+// Issue is a problem found in a configuration source
+type Issue struct {
+	File    string
+	Line    int // 0 when the position is unknown
+	Column  int
+	Message string
+	Warning bool // deprecations and outdated formats, which still load
+}
+
+// String formats the issue like a compiler error, e.g. ".codegenius.yaml:12:3: unknown key"
+func (i Issue) String() string {
+	switch {
+	case i.Line > 0 && i.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s%s", i.File, i.Line, i.Column, i.severity(), i.Message)
+	case i.Line > 0:
+		return fmt.Sprintf("%s:%d: %s%s", i.File, i.Line, i.severity(), i.Message)
+	default:
+		return fmt.Sprintf("%s: %s%s", i.File, i.severity(), i.Message)
+	}
+}
+
+// severity prefixes warnings so they stand out from errors
+func (i Issue) severity() string {
+	if i.Warning {
+		return "warning: "
+	}
+	return ""
+}
+
+// HasErrors reports whether any issue is more than a warning
+func HasErrors(issues []Issue) bool {
+	return len(Errors(issues)) > 0
+}
+
+// Errors returns the issues that are errors rather than warnings
+func Errors(issues []Issue) []Issue {
+	var errors []Issue
+	for _, issue := range issues {
+		if !issue.Warning {
+			errors = append(errors, issue)
+		}
+	}
+	return errors
+}
+
+// valueChecks are semantic checks on scalar values, keyed by dotted path.
+// List items are checked with the path of the list itself. Each check returns
+// "" for a valid value or a message describing the problem.
+var valueChecks = map[string]func(value string) string{
+	"ai.model":                    checkModel,
+	"ai.max_tokens":               checkPositive,
+	"ai.max_file_diff_bytes":      checkNonNegative,
+	"review.enabled_types":        checkReviewType,
+	"review.security_patterns":    checkRegex,
+	"project.scopes":              checkNonEmpty,
+	"project.scope_map.pattern":   checkNonEmpty,
+	"project.scope_map.scope":     checkNonEmpty,
+	"commit.trailers.key":         checkTrailerKey,
+	"commit.trailers.value":       checkNonEmpty,
+	"history.session_gap_minutes": checkPositive,
+	"history.lead_in_minutes":     checkNonNegative,
+	"history.max_age_days":        checkNonNegative,
+	"history.max_entries":         checkNonNegative,
+}
+
+// Validate checks every configuration source (global file, repository file,
+// nested directory overrides and environment variables) for syntax errors,
+// unknown keys, wrongly typed values and semantic problems. It does not
+// require Load to have succeeded.
+func (m *Manager) Validate() []Issue {
+	if m.root == "" {
+		m.root = findRepoRoot()
+	}
+
+	var issues []Issue
+	for _, path := range m.configFiles() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			issues = append(issues, Issue{File: path, Message: err.Error()})
+			continue
+		}
+		issues = append(issues, validateDocument(path, data)...)
+	}
+
+	if _, err := envLayer(); err != nil {
+		issues = append(issues, Issue{File: OriginEnv, Message: err.Error()})
+	}
+	return issues
+}
+
+// configFiles lists the existing config files that apply to the repository:
+// the global file, the root file and every nested override
+func (m *Manager) configFiles() []string {
+	var files []string
+	if global := GlobalConfigPath(); global != "" {
+		if _, err := os.Stat(global); err == nil {
+			files = append(files, global)
+		}
+	}
+
+	if _, err := os.Stat(m.repoConfigPath()); err == nil {
+		files = append(files, m.repoConfigPath())
+	}
+
+	// Nested overrides: tracked or untracked-but-not-ignored files below the root
+	cmd := exec.Command("git", "ls-files", "--cached", "--others", "--exclude-standard", "--", "*/"+configFile)
+	cmd.Dir = m.root
+	output, err := cmd.Output()
+	if err != nil {
+		return files
+	}
+	for _, file := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if file == "" {
+			continue
+		}
+		path := filepath.Join(m.root, filepath.FromSlash(file))
+		if _, err := os.Stat(path); err == nil && !containsPath(files, path) {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// containsPath reports whether a list of paths already contains path
+func containsPath(paths []string, path string) bool {
+	for _, existing := range paths {
+		if existing == path {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDocument parses one config file and checks it against Config
+func validateDocument(file string, data []byte) []Issue {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		issue := Issue{File: file, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+		if match := lineRegex.FindStringSubmatch(issue.Message); match != nil {
+			issue.Line, _ = strconv.Atoi(match[1])
+			issue.Message = strings.TrimPrefix(strings.TrimPrefix(issue.Message, match[0]), ": ")
+		}
+		return []Issue{issue}
+	}
+	if len(document.Content) == 0 {
+		return nil // an empty file is valid
+	}
+
+	validator := &validator{file: file}
+	validator.walk(document.Content[0], reflect.TypeOf(Config{}), "")
+	validator.checkVersion(document.Content[0])
+	return validator.issues
+}
+
+// validator walks a YAML node tree alongside the Go type it decodes into
+type validator struct {
+	file   string
+	issues []Issue
+}
+
+// report records an issue at a node's position
+func (v *validator) report(node *yamlv3.Node, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{
+		File:    v.file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// warn records a warning at a node's position
+func (v *validator) warn(node *yamlv3.Node, format string, args ...interface{}) {
+	v.report(node, format, args...)
+	v.issues[len(v.issues)-1].Warning = true
+}
+
+// checkVersion flags documents written for an older or newer config format.
+// An older format still loads, so its warning is only shown by config
+// validate; startup reports errors and migrations that change settings.
+func (v *validator) checkVersion(document *yamlv3.Node) {
+	if document.Kind != yamlv3.MappingNode {
+		return
+	}
+	node := findNode(document, []string{"version"})
+	version, err := documentVersion(document)
+	switch {
+	case err != nil:
+		return // reported as a type error by walk
+	case version > CurrentVersion:
+		v.report(node, "config format version %d is newer than this release supports (%d); please upgrade codegenius", version, CurrentVersion)
+	case version < CurrentVersion:
+		if node == nil {
+			node = document
+		}
+		v.warn(node, "config format version %d is outdated (current is %d); run 'codegenius config migrate'", version, CurrentVersion)
+	}
+}
+
+// walk validates node as a value of type t found at the dotted path
+func (v *validator) walk(node *yamlv3.Node, t reflect.Type, path string) {
+	if node.Kind == yamlv3.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yamlv3.ScalarNode && node.Tag == "!!null" {
+		return // an empty value keeps the inherited setting
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		v.walkStruct(node, t, path)
+	case reflect.Slice:
+		if node.Kind != yamlv3.SequenceNode {
+			v.report(node, "%s must be a list", displayPath(path))
+			return
+		}
+		for _, item := range node.Content {
+			v.walk(item, t.Elem(), path)
+		}
+	case reflect.Map:
+		if node.Kind != yamlv3.MappingNode {
+			v.report(node, "%s must be a mapping", displayPath(path))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.walk(node.Content[i+1], t.Elem(), path+"."+node.Content[i].Value)
+		}
+	default:
+		v.walkScalar(node, t, path)
+	}
+}
+
+// walkStruct checks the keys of a mapping against the struct's YAML fields
+func (v *validator) walkStruct(node *yamlv3.Node, t reflect.Type, path string) {
+	if node.Kind != yamlv3.MappingNode {
+		v.report(node, "%s must be a mapping", displayPath(path))
+		return
+	}
+
+	fields := make(map[string]reflect.Type)
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := yamlName(t.Field(i)); name != "" {
+			fields[name] = t.Field(i).Type
+			names = append(names, name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		childPath := joinPath(path, key.Value)
+
+		fieldType, ok := fields[key.Value]
+		if !ok {
+			if suggestion := closestName(key.Value, names); suggestion != "" {
+				v.report(key, "unknown key %q in %s (did you mean %q?)", key.Value, displayPath(path), suggestion)
+			} else {
+				v.report(key, "unknown key %q in %s", key.Value, displayPath(path))
+			}
+			continue
+		}
+		if seen[key.Value] {
+			v.report(key, "duplicate key %q in %s", key.Value, displayPath(path))
+		}
+		seen[key.Value] = true
+
+		for _, deprecated := range deprecatedKeys {
+			if deprecated.key == childPath {
+				v.warn(key, "%s is deprecated, use %s instead (%s)", childPath, deprecated.replacement, deprecated.hint)
+			}
+		}
+
+		v.walk(value, fieldType, childPath)
+	}
+}
+
+// walkScalar checks that a scalar decodes into t and passes its semantic check
+func (v *validator) walkScalar(node *yamlv3.Node, t reflect.Type, path string) {
+	if node.Kind != yamlv3.ScalarNode {
+		v.report(node, "%s must be a single %s value", displayPath(path), typeName(t))
+		return
+	}
+	if err := node.Decode(reflect.New(t).Interface()); err != nil {
+		v.report(node, "%s must be %s, got %q", displayPath(path), typeName(t), node.Value)
+		return
+	}
+	if check, ok := valueChecks[path]; ok {
+		if message := check(node.Value); message != "" {
+			v.report(node, "%s: %s", displayPath(path), message)
+		}
+	}
+}
+
+// joinPath appends a key to a dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayPath names a dotted path in messages, using "the top level" for the root
+func displayPath(path string) string {
+	if path == "" {
+		return "the top level"
+	}
+	return path
+}
+
+// typeName describes a Go type in user terms
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int64:
+		return "a whole number"
+	default:
+		return "text"
+	}
+}
+
+// closestName suggests the known name nearest to a misspelt key
+func closestName(key string, names []string) string {
+	best, bestDistance := "", 3 // only suggest names at most two edits away
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		if distance := editDistance(key, name); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// minInt returns the smallest of its arguments
+func minInt(values ...int) int {
+	smallest := values[0]
+	for _, value := range values[1:] {
+		if value < smallest {
+			smallest = value
+		}
+	}
+	return smallest
+}
+
+// checkModel rejects models the Gemini provider does not offer
+func checkModel(value string) string {
+	for _, model := range KnownModels {
+		if value == model {
+			return ""
+		}
+	}
+	return fmt.Sprintf("unknown model %q for provider gemini (known: %s)", value, strings.Join(KnownModels, ", "))
+}
+
+// checkReviewType rejects review types without a prompt
+func checkReviewType(value string) string {
+	for _, reviewType := range KnownReviewTypes {
+		if value == reviewType {
+			return ""
+		}
+	}
+	return fmt.Sprintf("unknown review type %q (known: %s)", value, strings.Join(KnownReviewTypes, ", "))
+}
+
+// checkRegex rejects patterns that do not compile
+func checkRegex(value string) string {
+	if _, err := regexp.Compile(value); err != nil {
+		return fmt.Sprintf("invalid regular expression: %v", err)
+	}
+	return ""
+}
+
+// checkNonEmpty rejects blank values
+func checkNonEmpty(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "must not be empty"
+	}
+	return ""
+}
+
+// checkPositive rejects zero and negative numbers
+func checkPositive(value string) string {
+	if number, err := strconv.Atoi(value); err == nil && number <= 0 {
+		return "must be greater than zero"
+	}
+	return ""
+}
+
+// checkNonNegative rejects negative numbers
+func checkNonNegative(value string) string {
+	if number, err := strconv.Atoi(value); err == nil && number < 0 {
+		return "must not be negative (use 0 to disable the limit)"
+	}
+	return ""
+}
+
+// checkTrailerKey rejects trailer keys git would not recognise
+func checkTrailerKey(value string) string {
+	if strings.TrimSpace(value) == "" || strings.ContainsAny(value, " \t:") {
+		return fmt.Sprintf("invalid trailer key %q, expected a single word such as \"Reviewed-by\"", value)
+	}
+	return ""
+}
@@ -0,0 +1,423 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// generatedHeaderRegex matches the conventional "Code generated ... DO NOT EDIT." marker
+var generatedHeaderRegex = regexp.MustCompile(`(?i)code generated .*do not edit`)
+
+// generatedHeaderLines is how far into a file the generated marker is searched for
+const generatedHeaderLines = 5
+
+// zeroBlob is the object id git prints for a missing side of a diff
+const zeroBlob = "0000000000000000000000000000000000000000"
+
+// hunkHeaderRegex matches "@@ -a,b +c,d @@ section" hunk headers
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// numstatEntry is one record of `git diff --numstat -z`
+type numstatEntry struct {
+	additions int
+	deletions int
+	binary    bool
+	oldPath   string
+	path      string
+}
+
+// GetStagedDiff returns the staged changes as a structured diff
+func (r *Repository) GetStagedDiff() (*interfaces.Diff, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return nil, err
+	}
+	return r.structuredDiff("diff", "--cached")
+}
+
+// GetAmendDiff returns the structured diff an amended HEAD would introduce:
+// HEAD's own changes plus anything staged on top of them
+func (r *Repository) GetAmendDiff() (*interfaces.Diff, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return nil, err
+	}
+
+	base, err := r.ResolveRevision("HEAD^")
+	if err != nil {
+		// Amending the root commit: compare against the empty tree
+		cmd := exec.Command("git", "hash-object", "-t", "tree", "--stdin")
+		cmd.Dir = r.workingDir
+		cmd.Stdin = strings.NewReader("")
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diff for amend: %v", err)
+		}
+		base = strings.TrimSpace(string(output))
+	}
+	return r.structuredDiff("diff", "--cached", base)
+}
+
+// structuredDiff runs a diff-producing git command twice, once for the patch
+// and once for `--numstat -z`, and merges both into a Diff
+func (r *Repository) structuredDiff(args ...string) (*interfaces.Diff, error) {
+	patchArgs := append(append([]string{}, args...), "-M", "--full-index", "--no-color", "--no-ext-diff")
+	cmd := exec.Command("git", patchArgs...)
+	cmd.Dir = r.workingDir
+	patch, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff: %v", err)
+	}
+
+	numstatArgs := append(append([]string{}, args...), "-M", "-z", "--numstat")
+	cmd = exec.Command("git", numstatArgs...)
+	cmd.Dir = r.workingDir
+	numstat, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff stats: %v", err)
+	}
+
+	diff := ParseDiff(string(patch))
+	applyNumstat(diff, parseNumstat(string(numstat)))
+	r.annotateDiff(diff)
+	return diff, nil
+}
+
+// annotateDiff fills in blob sizes and generated-file markers that cannot be
+// derived from the patch text alone
+func (r *Repository) annotateDiff(diff *interfaces.Diff) {
+	sizes := r.blobSizes(diff)
+	generated := r.generatedAttributes(diff.Paths())
+
+	for i := range diff.Files {
+		file := &diff.Files[i]
+		file.OldSize = sizes[file.OldBlob]
+		file.NewSize = sizes[file.NewBlob]
+
+		if file.Binary {
+			file.AddedBytes = int(file.NewSize)
+			file.RemovedBytes = int(file.OldSize)
+			continue
+		}
+
+		file.Generated = generated[file.Path] || r.hasGeneratedHeader(*file)
+	}
+}
+
+// blobSizes looks up the size of every blob referenced by the diff in a single
+// `git cat-file --batch-check` call
+func (r *Repository) blobSizes(diff *interfaces.Diff) map[string]int64 {
+	sizes := make(map[string]int64)
+
+	var request strings.Builder
+	for _, file := range diff.Files {
+		for _, blob := range []string{file.OldBlob, file.NewBlob} {
+			if blob != "" && blob != zeroBlob {
+				request.WriteString(blob + "\n")
+			}
+		}
+	}
+	if request.Len() == 0 {
+		return sizes
+	}
+
+	cmd := exec.Command("git", "cat-file", "--batch-check=%(objectname) %(objectsize)")
+	cmd.Dir = r.workingDir
+	cmd.Stdin = strings.NewReader(request.String())
+	output, err := cmd.Output()
+	if err != nil {
+		return sizes
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue // "<id> missing"
+		}
+		if size, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			sizes[fields[0]] = size
+		}
+	}
+	return sizes
+}
+
+// generatedAttributes returns the paths marked linguist-generated in .gitattributes
+func (r *Repository) generatedAttributes(paths []string) map[string]bool {
+	generated := make(map[string]bool)
+	if len(paths) == 0 {
+		return generated
+	}
+
+	args := append([]string{"check-attr", "-z", "linguist-generated", "--"}, paths...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return generated
+	}
+
+	// Output is a sequence of "<path>\0<attribute>\0<value>\0" records
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if value := fields[i+2]; value == "set" || value == "true" {
+			generated[fields[i]] = true
+		}
+	}
+	return generated
+}
+
+// hasGeneratedHeader reports whether the new version of a file starts with a
+// "Code generated ... DO NOT EDIT" comment, checking the hunks first and only
+// reading the blob when the top of the file is not part of the diff
+func (r *Repository) hasGeneratedHeader(file interfaces.FileDiff) bool {
+	if file.Status == interfaces.FileDeleted {
+		return false
+	}
+
+	for _, hunk := range file.Hunks {
+		if hunk.NewStart > generatedHeaderLines {
+			break
+		}
+		for _, line := range hunk.Lines {
+			if line.NewNumber == 0 {
+				continue
+			}
+			if line.NewNumber > generatedHeaderLines {
+				break
+			}
+			if generatedHeaderRegex.MatchString(line.Content) {
+				return true
+			}
+		}
+		if hunk.NewStart <= 1 {
+			// The top of the file is in the diff, so there is no need to read the blob
+			return false
+		}
+	}
+
+	if file.NewBlob == "" || file.NewBlob == zeroBlob {
+		return false
+	}
+
+	cmd := exec.Command("git", "cat-file", "blob", file.NewBlob)
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+
+	lines := strings.SplitN(string(output), "\n", generatedHeaderLines+1)
+	for i := 0; i < len(lines) && i < generatedHeaderLines; i++ {
+		if generatedHeaderRegex.MatchString(lines[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNumstat parses NUL-separated `--numstat -z` output. Renames and copies
+// are written as "add\tdel\t\0old\0new\0", other files as "add\tdel\tpath\0".
+func parseNumstat(output string) []numstatEntry {
+	fields := strings.Split(output, "\x00")
+	var entries []numstatEntry
+
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		entry := numstatEntry{binary: parts[0] == "-" && parts[1] == "-"}
+		entry.additions, _ = strconv.Atoi(parts[0])
+		entry.deletions, _ = strconv.Atoi(parts[1])
+
+		if parts[2] == "" && i+2 < len(fields) {
+			entry.oldPath = fields[i+1]
+			entry.path = fields[i+2]
+			i += 2
+		} else {
+			entry.path = parts[2]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// applyNumstat copies authoritative line counts and binary markers onto the parsed files
+func applyNumstat(diff *interfaces.Diff, entries []numstatEntry) {
+	byPath := make(map[string]numstatEntry, len(entries))
+	for _, entry := range entries {
+		byPath[entry.path] = entry
+	}
+
+	for i := range diff.Files {
+		file := &diff.Files[i]
+		entry, ok := byPath[file.Path]
+		if !ok {
+			continue
+		}
+		file.Additions = entry.additions
+		file.Deletions = entry.deletions
+		file.Binary = file.Binary || entry.binary
+		if entry.oldPath != "" && file.OldPath == "" {
+			file.OldPath = entry.oldPath
+		}
+	}
+}
+
+// ParseDiff parses unified diff text (as produced by `git diff` or `git show`)
+// into a structured Diff. Line counts are derived from the hunks; binary files
+// are detected from "Binary files ... differ" and "GIT binary patch" markers.
+func ParseDiff(text string) *interfaces.Diff {
+	diff := &interfaces.Diff{Files: make([]interfaces.FileDiff, 0)}
+	var current *interfaces.FileDiff
+	var oldLine, newLine int
+
+	lines := strings.Split(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			oldPath, newPath := pathsFromDiffHeader(line)
+			diff.Files = append(diff.Files, interfaces.FileDiff{
+				Path:    newPath,
+				OldPath: oldPath,
+				Status:  interfaces.FileModified,
+				Header:  []string{line},
+			})
+			current = &diff.Files[len(diff.Files)-1]
+		case current == nil:
+			// Ignore anything before the first file header (e.g. commit metadata)
+		case strings.HasPrefix(line, "@@"):
+			hunk := parseHunkHeader(line)
+			oldLine, newLine = hunk.OldStart, hunk.NewStart
+			current.Hunks = append(current.Hunks, hunk)
+		case len(current.Hunks) > 0:
+			hunk := &current.Hunks[len(current.Hunks)-1]
+			parsed := interfaces.Line{Kind: interfaces.LineContext}
+			if line != "" {
+				parsed.Kind = interfaces.LineKind(line[0])
+				parsed.Content = line[1:]
+			}
+			switch parsed.Kind {
+			case interfaces.LineAdded:
+				parsed.NewNumber = newLine
+				newLine++
+				current.Additions++
+				current.AddedBytes += len(parsed.Content) + 1
+			case interfaces.LineRemoved:
+				parsed.OldNumber = oldLine
+				oldLine++
+				current.Deletions++
+				current.RemovedBytes += len(parsed.Content) + 1
+			case interfaces.LineContext:
+				parsed.OldNumber, parsed.NewNumber = oldLine, newLine
+				oldLine++
+				newLine++
+			}
+			hunk.Lines = append(hunk.Lines, parsed)
+		default:
+			current.Header = append(current.Header, line)
+			parseExtendedHeader(current, line)
+		}
+	}
+
+	for i := range diff.Files {
+		if diff.Files[i].Status == interfaces.FileModified && diff.Files[i].OldPath == diff.Files[i].Path {
+			diff.Files[i].OldPath = ""
+		}
+	}
+
+	return diff
+}
+
+// pathsFromDiffHeader extracts the old and new paths from "diff --git a/x b/y".
+// Either path may be C-quoted (`"b/na\303\257ve.go"`). The result is refined
+// by the extended header lines, which are unambiguous even when paths contain spaces.
+func pathsFromDiffHeader(line string) (string, string) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	var oldPath, newPath string
+	if quoted, err := strconv.QuotedPrefix(rest); err == nil {
+		oldPath, newPath = unquotePath(quoted), strings.TrimPrefix(rest[len(quoted):], " ")
+	} else if index := strings.LastIndex(rest, ` "b/`); index != -1 && strings.HasSuffix(rest, `"`) {
+		oldPath, newPath = rest[:index], rest[index+1:]
+	} else if index := strings.LastIndex(rest, " b/"); index != -1 {
+		oldPath, newPath = rest[:index], rest[index+1:]
+	} else {
+		return rest, rest
+	}
+	return strings.TrimPrefix(oldPath, "a/"), strings.TrimPrefix(unquotePath(newPath), "b/")
+}
+
+// parseExtendedHeader updates a file diff from one git extended header line
+func parseExtendedHeader(file *interfaces.FileDiff, line string) {
+	switch {
+	case strings.HasPrefix(line, "new file mode "):
+		file.Status = interfaces.FileAdded
+		file.NewMode = strings.TrimPrefix(line, "new file mode ")
+		file.OldPath = ""
+	case strings.HasPrefix(line, "deleted file mode "):
+		file.Status = interfaces.FileDeleted
+		file.OldMode = strings.TrimPrefix(line, "deleted file mode ")
+		file.OldPath = file.Path
+	case strings.HasPrefix(line, "old mode "):
+		file.OldMode = strings.TrimPrefix(line, "old mode ")
+	case strings.HasPrefix(line, "new mode "):
+		file.NewMode = strings.TrimPrefix(line, "new mode ")
+	case strings.HasPrefix(line, "rename from "):
+		file.Status = interfaces.FileRenamed
+		file.OldPath = unquotePath(strings.TrimPrefix(line, "rename from "))
+	case strings.HasPrefix(line, "rename to "):
+		file.Path = unquotePath(strings.TrimPrefix(line, "rename to "))
+	case strings.HasPrefix(line, "copy from "):
+		file.Status = interfaces.FileCopied
+		file.OldPath = unquotePath(strings.TrimPrefix(line, "copy from "))
+	case strings.HasPrefix(line, "copy to "):
+		file.Path = unquotePath(strings.TrimPrefix(line, "copy to "))
+	case strings.HasPrefix(line, "similarity index "):
+		file.Similarity, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "similarity index "), "%"))
+	case strings.HasPrefix(line, "index "):
+		// "index abc..def 100644" carries the blob ids, and the mode when it did not change
+		fields := strings.Fields(line)
+		if blobs := strings.SplitN(fields[1], "..", 2); len(blobs) == 2 {
+			file.OldBlob, file.NewBlob = blobs[0], blobs[1]
+		}
+		if len(fields) == 3 && file.NewMode == "" {
+			file.OldMode, file.NewMode = fields[2], fields[2]
+		}
+	case strings.HasPrefix(line, "+++ b/") && file.Status != interfaces.FileDeleted:
+		// git terminates paths containing spaces with a tab
+		file.Path = strings.TrimSuffix(strings.TrimPrefix(line, "+++ b/"), "\t")
+	case strings.HasPrefix(line, `+++ "b/`) && file.Status != interfaces.FileDeleted:
+		// paths with tabs, newlines or non-ASCII bytes are C-quoted
+		file.Path = strings.TrimPrefix(unquotePath(strings.TrimSuffix(strings.TrimPrefix(line, "+++ "), "\t")), "b/")
+	case strings.HasPrefix(line, "Binary files "), strings.HasPrefix(line, "GIT binary patch"):
+		file.Binary = true
+	}
+}
+
+// parseHunkHeader parses the ranges and section heading of a hunk header
+func parseHunkHeader(header string) interfaces.Hunk {
+	hunk := interfaces.Hunk{Header: header, OldLines: 1, NewLines: 1}
+	matches := hunkHeaderRegex.FindStringSubmatch(header)
+	if matches == nil {
+		return hunk
+	}
+
+	hunk.OldStart, _ = strconv.Atoi(matches[1])
+	if matches[2] != "" {
+		hunk.OldLines, _ = strconv.Atoi(matches[2])
+	}
+	hunk.NewStart, _ = strconv.Atoi(matches[3])
+	if matches[4] != "" {
+		hunk.NewLines, _ = strconv.Atoi(matches[4])
+	}
+	hunk.Section = matches[5]
+	return hunk
+}
@@ -0,0 +1,240 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+func TestParseDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []interfaces.FileDiff
+	}{
+		{
+			name: "modified file",
+			text: `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@ package main
+ package main
+-var a = 1
++var a = 2
+ var b = 3
+`,
+			want: []interfaces.FileDiff{{Path: "main.go", Status: interfaces.FileModified, OldMode: "100644", NewMode: "100644", OldBlob: "1111111", NewBlob: "2222222", Additions: 1, Deletions: 1}},
+		},
+		{
+			name: "added and deleted files",
+			text: `diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,2 @@
++package main
++
+diff --git a/old.go b/old.go
+deleted file mode 100755
+index 4444444..0000000
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package main
+`,
+			want: []interfaces.FileDiff{
+				{Path: "new.go", Status: interfaces.FileAdded, NewMode: "100644", OldBlob: "0000000", NewBlob: "3333333", Additions: 2},
+				{Path: "old.go", OldPath: "old.go", Status: interfaces.FileDeleted, OldMode: "100755", OldBlob: "4444444", NewBlob: "0000000", Deletions: 1},
+			},
+		},
+		{
+			name: "rename with mode change",
+			text: `diff --git a/cmd/run.sh b/scripts/run.sh
+old mode 100644
+new mode 100755
+similarity index 90%
+rename from cmd/run.sh
+rename to scripts/run.sh
+`,
+			want: []interfaces.FileDiff{{Path: "scripts/run.sh", OldPath: "cmd/run.sh", Status: interfaces.FileRenamed, Similarity: 90, OldMode: "100644", NewMode: "100755"}},
+		},
+		{
+			name: "copy",
+			text: `diff --git a/a.go b/b.go
+similarity index 100%
+copy from a.go
+copy to b.go
+`,
+			want: []interfaces.FileDiff{{Path: "b.go", OldPath: "a.go", Status: interfaces.FileCopied, Similarity: 100}},
+		},
+		{
+			name: "binary file",
+			text: `diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+`,
+			want: []interfaces.FileDiff{{Path: "logo.png", Status: interfaces.FileModified, OldMode: "100644", NewMode: "100644", OldBlob: "5555555", NewBlob: "6666666", Binary: true}},
+		},
+		{
+			name: "path with spaces",
+			text: "diff --git a/with space.go b/with space.go\n" +
+				"new file mode 100644\n" +
+				"--- /dev/null\n" +
+				"+++ b/with space.go\t\n" +
+				"@@ -0,0 +1 @@\n" +
+				"+x\n",
+			want: []interfaces.FileDiff{{Path: "with space.go", Status: interfaces.FileAdded, NewMode: "100644", Additions: 1}},
+		},
+		{
+			name: "quoted paths",
+			text: `diff --git "a/dir/na\303\257ve.go" "b/dir/na\303\257ve2.go"
+similarity index 100%
+rename from "dir/na\303\257ve.go"
+rename to "dir/na\303\257ve2.go"
+diff --git "a/tab\tname.go" "b/tab\tname.go"
+new file mode 100644
+--- /dev/null
++++ "b/tab\tname.go"
+@@ -0,0 +1 @@
++x
+`,
+			want: []interfaces.FileDiff{
+				{Path: "dir/naïve2.go", OldPath: "dir/naïve.go", Status: interfaces.FileRenamed, Similarity: 100},
+				{Path: "tab\tname.go", Status: interfaces.FileAdded, NewMode: "100644", Additions: 1},
+			},
+		},
+		{
+			name: "commit metadata before the first file",
+			text: `commit 0123456789abcdef
+Author: Someone <someone@example.com>
+
+    diff --git a/not b/a file
+
+diff --git a/x.go b/x.go
+--- a/x.go
++++ b/x.go
+@@ -1 +1 @@
+-a
++b
+`,
+			want: []interfaces.FileDiff{{Path: "x.go", Status: interfaces.FileModified, Additions: 1, Deletions: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := ParseDiff(tt.text)
+			got := make([]interfaces.FileDiff, len(diff.Files))
+			for i, file := range diff.Files {
+				got[i] = summarise(file)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDiff() files =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiffLineNumbers(t *testing.T) {
+	diff := ParseDiff(`diff --git a/f.txt b/f.txt
+--- a/f.txt
++++ b/f.txt
+@@ -10,4 +10,5 @@ func section()
+ ten
+-eleven
++eleven!
++eleven and a half
+ twelve
+
+\ No newline at end of file
+`)
+	if len(diff.Files) != 1 || len(diff.Files[0].Hunks) != 1 {
+		t.Fatalf("ParseDiff() = %+v, want one file with one hunk", diff)
+	}
+
+	hunk := diff.Files[0].Hunks[0]
+	if hunk.OldStart != 10 || hunk.OldLines != 4 || hunk.NewStart != 10 || hunk.NewLines != 5 || hunk.Section != "func section()" {
+		t.Errorf("hunk header = %+v", hunk)
+	}
+
+	want := []interfaces.Line{
+		{Kind: interfaces.LineContext, Content: "ten", OldNumber: 10, NewNumber: 10},
+		{Kind: interfaces.LineRemoved, Content: "eleven", OldNumber: 11},
+		{Kind: interfaces.LineAdded, Content: "eleven!", NewNumber: 11},
+		{Kind: interfaces.LineAdded, Content: "eleven and a half", NewNumber: 12},
+		{Kind: interfaces.LineContext, Content: "twelve", OldNumber: 12, NewNumber: 13},
+		{Kind: interfaces.LineContext, OldNumber: 13, NewNumber: 14},
+		{Kind: interfaces.LineNoNewline, Content: " No newline at end of file"},
+	}
+	if !reflect.DeepEqual(hunk.Lines, want) {
+		t.Errorf("hunk lines =\n%+v\nwant\n%+v", hunk.Lines, want)
+	}
+}
+
+func TestParseDiffKeepsHeader(t *testing.T) {
+	diff := ParseDiff("diff --git a/x b/x\nindex 1..2 100644\n--- a/x\n+++ b/x\n")
+	if len(diff.Files) != 1 {
+		t.Fatalf("ParseDiff() files = %d, want 1", len(diff.Files))
+	}
+	if got := strings.Join(diff.Files[0].Header, "\n"); got != "diff --git a/x b/x\nindex 1..2 100644\n--- a/x\n+++ b/x" {
+		t.Errorf("header = %q", got)
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []numstatEntry
+	}{
+		{
+			name:   "empty",
+			output: "",
+			want:   nil,
+		},
+		{
+			name:   "modified and binary files",
+			output: "3\t1\tmain.go\x00-\t-\tlogo.png\x00",
+			want: []numstatEntry{
+				{additions: 3, deletions: 1, path: "main.go"},
+				{binary: true, path: "logo.png"},
+			},
+		},
+		{
+			name:   "rename",
+			output: "0\t0\t\x00cmd/run.sh\x00scripts/run.sh\x002\t0\tREADME.md\x00",
+			want: []numstatEntry{
+				{oldPath: "cmd/run.sh", path: "scripts/run.sh"},
+				{additions: 2, path: "README.md"},
+			},
+		},
+		{
+			name:   "paths are not quoted",
+			output: "1\t0\ttab\tname.go\x001\t0\tdir/naïve.go\x00",
+			want: []numstatEntry{
+				{additions: 1, path: "tab\tname.go"},
+				{additions: 1, path: "dir/naïve.go"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNumstat(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNumstat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// summarise keeps the fields of a file diff that the parser derives from headers and hunks
+func summarise(file interfaces.FileDiff) interfaces.FileDiff {
+	file.Header = nil
+	file.Hunks = nil
+	file.AddedBytes, file.RemovedBytes = 0, 0
+	return file
+}
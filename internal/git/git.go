@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
@@ -27,21 +28,6 @@ func New() *Repository {
 	return &Repository{workingDir: "."}
 }
 
-// GetDiff returns the git diff for staged changes
-func (r *Repository) GetDiff() (string, error) {
-	if err := r.validateGitRepo(); err != nil {
-		return "", err
-	}
-
-	cmd := exec.Command("git", "diff", "--cached")
-	cmd.Dir = r.workingDir
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get git diff: %v", err)
-	}
-	return string(output), nil
-}
-
 // GetChangedFiles returns a list of files that have been changed
 func (r *Repository) GetChangedFiles() ([]string, error) {
 	if err := r.validateGitRepo(); err != nil {
@@ -117,8 +103,49 @@ func (r *Repository) HasStagedChanges() (bool, error) {
 	return false, nil // Exit code 0 means no differences
 }
 
-// CommitWithMessage commits the staged changes with the provided message
-func (r *Repository) CommitWithMessage(message string) error {
+// GetRepoRoot returns the absolute path of the top-level directory of the working tree
+func (r *Repository) GetRepoRoot() (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find repository root: %v", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetGitPath returns the absolute path of name inside the repository's git
+// directory. Linked worktrees share the main repository's directory.
+func (r *Repository) GetGitPath(name string) (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find git directory: %v", err)
+	}
+
+	dir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(dir) {
+		// Relative to the directory git ran in
+		dir = filepath.Join(r.workingDir, dir)
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find git directory: %v", err)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// CommitWithMessage commits the staged changes with the provided message and options
+func (r *Repository) CommitWithMessage(message string, opts interfaces.CommitOptions) error {
 	if err := r.validateGitRepo(); err != nil {
 		return err
 	}
@@ -127,7 +154,12 @@ func (r *Repository) CommitWithMessage(message string) error {
 		return fmt.Errorf("commit message cannot be empty")
 	}
 
-	cmd := exec.Command("git", "commit", "-m", message)
+	args := append([]string{"commit"}, commitArgs(opts)...)
+	args = append(args, "-m", message)
+
+	cmd := exec.Command("git", args...)
+	// Signing may prompt for a passphrase through pinentry or ssh-agent
+	cmd.Stdin = os.Stdin
 	cmd.Dir = r.workingDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -139,6 +171,41 @@ func (r *Repository) CommitWithMessage(message string) error {
 	return nil
 }
 
+// commitArgs converts commit options into `git commit` flags
+func commitArgs(opts interfaces.CommitOptions) []string {
+	var args []string
+	if opts.Sign {
+		args = append(args, "-S"+opts.SigningKey)
+	}
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if opts.Amend {
+		args = append(args, "--amend")
+	}
+	if opts.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Date != "" {
+		args = append(args, "--date="+opts.Date)
+	}
+	return args
+}
+
+// ResolveCommitOptions applies the configured signing defaults to per-commit options
+func ResolveCommitOptions(commitConfig interfaces.CommitConfig, opts interfaces.CommitOptions) interfaces.CommitOptions {
+	if commitConfig.Sign || opts.SigningKey != "" {
+		opts.Sign = true
+	}
+	if opts.SigningKey == "" {
+		opts.SigningKey = commitConfig.SigningKey
+	}
+	return opts
+}
+
 // EditCommitMessage opens an editor for the user to edit the commit message
 func (r *Repository) EditCommitMessage(message string) (string, error) {
 	if err := r.validateGitRepo(); err != nil {
@@ -186,59 +253,6 @@ func (r *Repository) EditCommitMessage(message string) (string, error) {
 	return strings.TrimSpace(string(editedMessage)), nil
 }
 
-// AnalyzeDiffContext analyzes git diff and extracts meaningful context
-func (r *Repository) AnalyzeDiffContext(diff string, ignorePatterns []string) (string, []string) {
-	lines := strings.Split(diff, "\n")
-	var significantChanges []string
-	var fileChanges []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Skip if should be ignored
-		shouldIgnore := false
-		for _, pattern := range ignorePatterns {
-			if strings.Contains(line, pattern) {
-				shouldIgnore = true
-				break
-			}
-		}
-		if shouldIgnore {
-			continue
-		}
-
-		// Track file changes
-		if strings.HasPrefix(line, "diff --git") {
-			parts := strings.Fields(line)
-			if len(parts) >= 4 {
-				file := strings.TrimPrefix(parts[3], "b/")
-				fileChanges = append(fileChanges, file)
-			}
-		}
-
-		// Analyze significant changes
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
-			if len(line) > 10 { // Ignore very short additions
-				significantChanges = append(significantChanges, line[1:])
-			}
-		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
-			if len(line) > 10 { // Ignore very short deletions
-				significantChanges = append(significantChanges, "REMOVED: "+line[1:])
-			}
-		}
-	}
-
-	maxChanges := min(5, len(significantChanges))
-	context := fmt.Sprintf("Modified files: %s\nKey changes: %s",
-		strings.Join(fileChanges, ", "),
-		strings.Join(significantChanges[:maxChanges], "; "))
-
-	return context, fileChanges
-}
-
 // validateGitRepo checks if the current directory is a Git repository
 func (r *Repository) validateGitRepo() error {
 	cmd := exec.Command("git", "rev-parse", "--git-dir")
@@ -291,11 +305,3 @@ func (r *Repository) GetStatus() (string, error) {
 
 	return string(output), nil
 }
-
-// min helper function
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// commitLogFormat starts each commit with a record separator, then prints the
+// SHA, author, ISO committer date and message separated by NULs. With -z the
+// numstat lines follow the message, also NUL-separated. The committer date is
+// when the work landed; the author date survives amends, rebases and --date.
+const commitLogFormat = "%x1e%H%x00%an <%ae>%x00%cI%x00%B"
+
+// GetCommitInfo returns the SHA, author, committer date, message and diff stats of a commit
+func (r *Repository) GetCommitInfo(rev string) (*interfaces.CommitInfo, error) {
+	commits, err := r.commitLog("-1", rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %v", rev, err)
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("failed to read commit %s: no such commit", rev)
+	}
+	return &commits[0], nil
+}
+
+// GetLog returns the non-merge commits reachable from HEAD, newest first,
+// optionally limited to those after since (any date git understands, e.g.
+// "2024-01-01" or "2 weeks ago") and by authors matching author
+func (r *Repository) GetLog(since, author string) ([]interfaces.CommitInfo, error) {
+	args := []string{"--no-merges"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	if author != "" {
+		args = append(args, "--author="+author)
+	}
+
+	commits, err := r.commitLog(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %v", err)
+	}
+	return commits, nil
+}
+
+// commitLog runs git log with numstat and parses every commit it prints
+func (r *Repository) commitLog(args ...string) ([]interfaces.CommitInfo, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", append([]string{"log", "--numstat", "-z", "--format=" + commitLogFormat}, args...)...)
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(string(output))
+}
+
+// parseCommitLog parses commitLogFormat records and their numstat entries
+func parseCommitLog(output string) ([]interfaces.CommitInfo, error) {
+	var commits []interfaces.CommitInfo
+	for _, record := range strings.Split(output, "\x1e") {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, "\x00", 5)
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected log output %q", record)
+		}
+
+		timestamp, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit date %q: %v", fields[2], err)
+		}
+
+		commit := interfaces.CommitInfo{
+			SHA:       fields[0],
+			Author:    fields[1],
+			Timestamp: timestamp,
+			Message:   strings.TrimSpace(fields[3]),
+		}
+		if len(fields) == 5 {
+			for _, entry := range parseNumstat(strings.TrimLeft(fields[4], "\n")) {
+				commit.FilesChanged++
+				commit.Insertions += entry.additions
+				commit.Deletions += entry.deletions
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
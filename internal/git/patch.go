@@ -0,0 +1,252 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// atomicHeaderMarkers identify file diffs whose header must be applied exactly
+// once, so their hunks cannot be spread across several commits
+var atomicHeaderMarkers = []string{
+	"new file mode", "deleted file mode", "old mode", "new mode",
+	"rename from", "copy from", "Binary files", "GIT binary patch",
+}
+
+// Chunk is the smallest part of a diff that can be committed on its own: a
+// single hunk, or every hunk of a file that has to be applied as a whole
+type Chunk struct {
+	File  int   // index into the parsed file list
+	Hunks []int // hunk indices within the file
+}
+
+// isAtomic reports whether a file diff has to be applied in one piece
+func isAtomic(file interfaces.FileDiff) bool {
+	if len(file.Hunks) <= 1 {
+		return true
+	}
+	for _, line := range file.Header {
+		for _, marker := range atomicHeaderMarkers {
+			if strings.HasPrefix(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SplitChunks breaks parsed file diffs into independently committable chunks
+func SplitChunks(files []interfaces.FileDiff) []Chunk {
+	var chunks []Chunk
+	for fileIndex, file := range files {
+		if isAtomic(file) {
+			hunks := make([]int, len(file.Hunks))
+			for i := range file.Hunks {
+				hunks[i] = i
+			}
+			chunks = append(chunks, Chunk{File: fileIndex, Hunks: hunks})
+			continue
+		}
+		for hunkIndex := range file.Hunks {
+			chunks = append(chunks, Chunk{File: fileIndex, Hunks: []int{hunkIndex}})
+		}
+	}
+	return chunks
+}
+
+// DescribeChunk renders a chunk for display or for an AI prompt, keeping at
+// most maxLines changed lines
+func DescribeChunk(files []interfaces.FileDiff, chunk Chunk, maxLines int) string {
+	file := files[chunk.File]
+
+	var description strings.Builder
+	description.WriteString(file.Path)
+	if len(chunk.Hunks) == 0 {
+		description.WriteString(" (" + headerSummary(file) + ")")
+	}
+
+	shown := 0
+	for _, hunkIndex := range chunk.Hunks {
+		hunk := file.Hunks[hunkIndex]
+		description.WriteString("\n" + hunk.Header)
+		for _, line := range hunk.Lines {
+			if line.Kind != interfaces.LineAdded && line.Kind != interfaces.LineRemoved {
+				continue
+			}
+			if shown >= maxLines {
+				description.WriteString("\n...")
+				return description.String()
+			}
+			description.WriteString("\n" + line.String())
+			shown++
+		}
+	}
+	return description.String()
+}
+
+// headerSummary describes a file diff without hunks (binary, rename or mode change)
+func headerSummary(file interfaces.FileDiff) string {
+	summaries := []struct {
+		marker  string
+		summary string
+	}{
+		{"Binary files", "binary change"},
+		{"GIT binary patch", "binary change"},
+		{"rename from", "renamed"},
+		{"new mode", "mode change"},
+		{"new file mode", "new empty file"},
+		{"deleted file mode", "deleted"},
+	}
+
+	for _, candidate := range summaries {
+		for _, line := range file.Header {
+			if strings.HasPrefix(line, candidate.marker) {
+				return candidate.summary
+			}
+		}
+	}
+	return "header only"
+}
+
+// BuildPatch renders the selected chunks back into a patch that `git apply` accepts
+func BuildPatch(files []interfaces.FileDiff, chunks []Chunk) string {
+	selected := make(map[int]map[int]bool)
+	for _, chunk := range chunks {
+		if selected[chunk.File] == nil {
+			selected[chunk.File] = make(map[int]bool)
+		}
+		for _, hunkIndex := range chunk.Hunks {
+			selected[chunk.File][hunkIndex] = true
+		}
+	}
+
+	var patch strings.Builder
+	for fileIndex, file := range files {
+		hunks, ok := selected[fileIndex]
+		if !ok {
+			continue
+		}
+		for _, line := range file.Header {
+			patch.WriteString(line + "\n")
+		}
+		for hunkIndex, hunk := range file.Hunks {
+			if !hunks[hunkIndex] {
+				continue
+			}
+			patch.WriteString(hunk.String())
+		}
+	}
+	return patch.String()
+}
+
+// NormalizeGroups drops unknown or duplicate chunk references (the first group
+// to claim a chunk keeps it) and removes groups left empty or without a message
+func NormalizeGroups(groups []interfaces.CommitGroup, chunkCount int) []interfaces.CommitGroup {
+	assigned := make(map[int]bool)
+	normalized := make([]interfaces.CommitGroup, 0, len(groups))
+
+	for _, group := range groups {
+		message := strings.TrimSpace(group.Message)
+		if message == "" {
+			continue
+		}
+
+		var chunks []int
+		for _, chunk := range group.Chunks {
+			if chunk < 0 || chunk >= chunkCount || assigned[chunk] {
+				continue
+			}
+			assigned[chunk] = true
+			chunks = append(chunks, chunk)
+		}
+		if len(chunks) > 0 {
+			normalized = append(normalized, interfaces.CommitGroup{Message: message, Chunks: chunks})
+		}
+	}
+
+	return normalized
+}
+
+// UnassignedChunks returns the chunks not claimed by any group
+func UnassignedChunks(groups []interfaces.CommitGroup, chunkCount int) []int {
+	assigned := make(map[int]bool)
+	for _, group := range groups {
+		for _, chunk := range group.Chunks {
+			assigned[chunk] = true
+		}
+	}
+
+	var remaining []int
+	for chunk := 0; chunk < chunkCount; chunk++ {
+		if !assigned[chunk] {
+			remaining = append(remaining, chunk)
+		}
+	}
+	return remaining
+}
+
+// AssignRemaining appends a catch-all group for any chunk the AI did not place,
+// so every chunk ends up in exactly one commit
+func AssignRemaining(groups []interfaces.CommitGroup, chunkCount int) []interfaces.CommitGroup {
+	if remaining := UnassignedChunks(groups, chunkCount); len(remaining) > 0 {
+		groups = append(groups, interfaces.CommitGroup{
+			Message: "chore: remaining changes",
+			Chunks:  remaining,
+		})
+	}
+	return groups
+}
+
+// PatchCommits turns commit groups into patches ready for CommitSeries
+func PatchCommits(files []interfaces.FileDiff, chunks []Chunk, groups []interfaces.CommitGroup) ([]interfaces.PatchCommit, error) {
+	commits := make([]interfaces.PatchCommit, 0, len(groups))
+	for _, group := range groups {
+		selected := make([]Chunk, 0, len(group.Chunks))
+		for _, index := range group.Chunks {
+			if index < 0 || index >= len(chunks) {
+				return nil, fmt.Errorf("unknown chunk %d in group %q", index, group.Message)
+			}
+			selected = append(selected, chunks[index])
+		}
+		commits = append(commits, interfaces.PatchCommit{
+			Message: group.Message,
+			Patch:   BuildPatch(files, selected),
+		})
+	}
+	return commits, nil
+}
+
+// SplitPlan holds a parsed staged diff broken into chunks for splitting
+type SplitPlan struct {
+	Files        []interfaces.FileDiff
+	Chunks       []Chunk
+	Descriptions []string
+}
+
+// NewSplitPlan parses a staged patch into chunks with prompt-ready descriptions
+func NewSplitPlan(patch string) *SplitPlan {
+	files := ParseDiff(patch).Files
+	chunks := SplitChunks(files)
+
+	descriptions := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		descriptions[i] = DescribeChunk(files, chunk, 40)
+	}
+
+	return &SplitPlan{Files: files, Chunks: chunks, Descriptions: descriptions}
+}
+
+// Commits converts commit groups into patch commits for this plan
+func (p *SplitPlan) Commits(groups []interfaces.CommitGroup) ([]interfaces.PatchCommit, error) {
+	return PatchCommits(p.Files, p.Chunks, groups)
+}
+
+// Label returns a one-line label for a chunk, e.g. "api/server.go @@ -10,6 +10,8 @@"
+func (p *SplitPlan) Label(chunk int) string {
+	lines := strings.SplitN(p.Descriptions[chunk], "\n", 3)
+	if len(lines) > 1 && strings.HasPrefix(lines[1], "@@") {
+		return lines[0] + " " + lines[1]
+	}
+	return lines[0]
+}
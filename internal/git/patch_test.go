@@ -0,0 +1,153 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+const (
+	twoHunkHeader = "diff --git a/server.go b/server.go\n" +
+		"index 1111111..2222222 100644\n" +
+		"--- a/server.go\n" +
+		"+++ b/server.go\n"
+	firstHunk = "@@ -1,3 +1,3 @@\n" +
+		" package server\n" +
+		"-const port = 80\n" +
+		"+const port = 8080\n" +
+		" \n"
+	secondHunk = "@@ -20,2 +20,3 @@ func Start() {\n" +
+		" \tlisten()\n" +
+		"+\tlog()\n" +
+		" }\n"
+	newFile = "diff --git a/notes.txt b/notes.txt\n" +
+		"new file mode 100644\n" +
+		"index 0000000..3333333\n" +
+		"--- /dev/null\n" +
+		"+++ b/notes.txt\n" +
+		"@@ -0,0 +1,2 @@\n" +
+		"+one\n" +
+		"+two\n"
+)
+
+func TestSplitChunks(t *testing.T) {
+	files := ParseDiff(twoHunkHeader + firstHunk + secondHunk + newFile).Files
+	want := []Chunk{
+		{File: 0, Hunks: []int{0}},
+		{File: 0, Hunks: []int{1}},
+		{File: 1, Hunks: []int{0}},
+	}
+	if got := SplitChunks(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitChunks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildPatch(t *testing.T) {
+	files := ParseDiff(twoHunkHeader + firstHunk + secondHunk + newFile).Files
+	chunks := SplitChunks(files)
+
+	tests := []struct {
+		name   string
+		chunks []Chunk
+		want   string
+	}{
+		{
+			name:   "first hunk",
+			chunks: []Chunk{chunks[0]},
+			want:   twoHunkHeader + firstHunk,
+		},
+		{
+			name:   "second hunk",
+			chunks: []Chunk{chunks[1]},
+			want:   twoHunkHeader + secondHunk,
+		},
+		{
+			name:   "hunks keep file order",
+			chunks: []Chunk{chunks[2], chunks[1], chunks[0]},
+			want:   twoHunkHeader + firstHunk + secondHunk + newFile,
+		},
+		{
+			name:   "new file",
+			chunks: []Chunk{chunks[2]},
+			want:   newFile,
+		},
+		{
+			name: "nothing selected",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildPatch(files, tt.chunks); got != tt.want {
+				t.Errorf("BuildPatch() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeGroups(t *testing.T) {
+	groups := []interfaces.CommitGroup{
+		{Message: " feat: first ", Chunks: []int{0, 2, 0}},
+		{Message: "", Chunks: []int{1}},
+		{Message: "fix: second", Chunks: []int{2, 5, -1}},
+		{Message: "docs: third", Chunks: []int{3}},
+	}
+	want := []interfaces.CommitGroup{
+		{Message: "feat: first", Chunks: []int{0, 2}},
+		{Message: "docs: third", Chunks: []int{3}},
+	}
+	if got := NormalizeGroups(groups, 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeGroups() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAssignRemaining(t *testing.T) {
+	tests := []struct {
+		name       string
+		groups     []interfaces.CommitGroup
+		chunkCount int
+		want       []interfaces.CommitGroup
+	}{
+		{
+			name:       "every chunk assigned",
+			groups:     []interfaces.CommitGroup{{Message: "feat: a", Chunks: []int{1, 0}}},
+			chunkCount: 2,
+			want:       []interfaces.CommitGroup{{Message: "feat: a", Chunks: []int{1, 0}}},
+		},
+		{
+			name:       "unassigned chunks go to a catch-all commit",
+			groups:     []interfaces.CommitGroup{{Message: "feat: a", Chunks: []int{1}}},
+			chunkCount: 4,
+			want: []interfaces.CommitGroup{
+				{Message: "feat: a", Chunks: []int{1}},
+				{Message: "chore: remaining changes", Chunks: []int{0, 2, 3}},
+			},
+		},
+		{
+			name:       "no groups",
+			chunkCount: 2,
+			want:       []interfaces.CommitGroup{{Message: "chore: remaining changes", Chunks: []int{0, 1}}},
+		},
+		{
+			name: "no chunks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AssignRemaining(tt.groups, tt.chunkCount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AssignRemaining() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchCommitsRejectsUnknownChunks(t *testing.T) {
+	files := ParseDiff(newFile).Files
+	groups := []interfaces.CommitGroup{{Message: "feat: a", Chunks: []int{0, 1}}}
+	if _, err := PatchCommits(files, SplitChunks(files), groups); err == nil {
+		t.Error("PatchCommits() accepted a chunk that does not exist")
+	}
+}
@@ -0,0 +1,363 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// backupRefPrefix is where safety refs are stored before history is rewritten
+const backupRefPrefix = "refs/codegenius/backup/"
+
+// rewordSequenceEditor is used as GIT_SEQUENCE_EDITOR during a reword rebase. It
+// leaves the todo list untouched except for inserting an `exec` after every
+// commit listed in the map file, which amends it with its replacement message.
+// Both "pick" and the abbreviated "p" (rebase.abbreviateCommands) are matched.
+const rewordSequenceEditor = `#!/bin/sh
+awk -v map="$CODEGENIUS_REWORD_MAP" '
+BEGIN { while ((getline line < map) > 0) messages[line] = 1 }
+{ print }
+$1 == "pick" || $1 == "p" { for (sha in messages) if (index(sha, $2) == 1) print "exec sh \"$CODEGENIUS_REWORD_AMEND\" " sha }
+' "$1" > "$1.codegenius" && mv "$1.codegenius" "$1"
+`
+
+// rewordAmendScript amends HEAD with the message stored for the SHA given as
+// its argument; %s is replaced by the quoted commit options
+const rewordAmendScript = `#!/bin/sh
+exec git commit --amend --only --allow-empty --cleanup=verbatim %s -F "$CODEGENIUS_REWORD_DIR/$1.txt"
+`
+
+// ResolveRevision resolves a revision such as HEAD or a short SHA to a full commit SHA
+func (r *Repository) ResolveRevision(rev string) (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision: %s", rev)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListCommits returns the commits in a revision range, oldest first
+func (r *Repository) ListCommits(revRange string) ([]string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", "rev-list", "--reverse", revRange)
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits in %s: %v", revRange, err)
+	}
+
+	commits := strings.Fields(string(output))
+	return commits, nil
+}
+
+// GetCommitDiff returns the structured diff introduced by a commit
+func (r *Repository) GetCommitDiff(rev string) (*interfaces.Diff, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return nil, err
+	}
+
+	diff, err := r.structuredDiff("show", "--format=", rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff for %s: %v", rev, err)
+	}
+	return diff, nil
+}
+
+// GetCommitMessage returns the full message of a commit
+func (r *Repository) GetCommitMessage(rev string) (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%B", rev)
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get message for %s: %v", rev, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RewordCommits replaces the messages of the given commits (keyed by full SHA),
+// signing the rewritten commits as opts asks. Rewording only HEAD uses
+// `git commit --amend`; older commits are rewritten with a non-interactive
+// rebase that amends each target through an `exec` step.
+func (r *Repository) RewordCommits(messages map[string]string, opts interfaces.CommitOptions) error {
+	if err := r.validateGitRepo(); err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	head, err := r.ResolveRevision("HEAD")
+	if err != nil {
+		return err
+	}
+
+	// Only the options that apply to an amend; the rest would change the commits
+	amendOpts := interfaces.CommitOptions{Sign: opts.Sign, SigningKey: opts.SigningKey, NoVerify: opts.NoVerify}
+
+	if message, ok := messages[head]; ok && len(messages) == 1 {
+		args := append([]string{"commit", "--amend", "--only", "--allow-empty", "--cleanup=verbatim"}, commitArgs(amendOpts)...)
+		cmd := exec.Command("git", append(args, "-m", message)...)
+		cmd.Dir = r.workingDir
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to amend HEAD: %v", err)
+		}
+		return r.verifyReword([]string{head}, []string{"HEAD"}, messages)
+	}
+
+	oldest, err := r.oldestCommit(messages)
+	if err != nil {
+		return err
+	}
+
+	rebaseBase := []string{"--root"}
+	revRange := "HEAD"
+	if parent, err := r.ResolveRevision(oldest + "^"); err == nil {
+		rebaseBase = []string{parent}
+		revRange = parent + "..HEAD"
+	}
+	// The rebase would flatten merges, including when it starts from the root
+	if r.hasMerges(revRange) {
+		return fmt.Errorf("cannot reword across merge commits; reword commits individually after the merge")
+	}
+
+	before, err := r.ListCommits(revRange)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "codegenius-reword-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Message files are named after their commit, so no path ends up in the todo list
+	var mapping strings.Builder
+	for sha, message := range messages {
+		messageFile := filepath.Join(tmpDir, sha+".txt")
+		if err := os.WriteFile(messageFile, []byte(message+"\n"), 0600); err != nil {
+			return fmt.Errorf("error writing message for %s: %v", sha, err)
+		}
+		mapping.WriteString(sha + "\n")
+	}
+
+	mapFile := filepath.Join(tmpDir, "map")
+	if err := os.WriteFile(mapFile, []byte(mapping.String()), 0600); err != nil {
+		return fmt.Errorf("error writing reword map: %v", err)
+	}
+
+	editorScript := filepath.Join(tmpDir, "sequence-editor.sh")
+	if err := os.WriteFile(editorScript, []byte(rewordSequenceEditor), 0700); err != nil {
+		return fmt.Errorf("error writing sequence editor: %v", err)
+	}
+
+	var quoted []string
+	for _, arg := range commitArgs(amendOpts) {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	amendScript := filepath.Join(tmpDir, "amend.sh")
+	if err := os.WriteFile(amendScript, []byte(fmt.Sprintf(rewordAmendScript, strings.Join(quoted, " "))), 0700); err != nil {
+		return fmt.Errorf("error writing amend script: %v", err)
+	}
+
+	args := append([]string{"rebase", "-i", "--autostash"}, rebaseBase...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.workingDir
+	cmd.Env = append(os.Environ(),
+		"GIT_SEQUENCE_EDITOR=sh "+shellQuote(editorScript),
+		"CODEGENIUS_REWORD_MAP="+mapFile,
+		"CODEGENIUS_REWORD_AMEND="+amendScript,
+		"CODEGENIUS_REWORD_DIR="+tmpDir,
+	)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		abort := exec.Command("git", "rebase", "--abort")
+		abort.Dir = r.workingDir
+		abort.Run()
+		return fmt.Errorf("reword rebase failed and was aborted: %v", err)
+	}
+
+	after, err := r.ListCommits(revRange)
+	if err != nil {
+		return err
+	}
+	if len(after) != len(before) {
+		return fmt.Errorf("reword rebase rewrote %d commits instead of %d; check the branch history", len(after), len(before))
+	}
+	return r.verifyReword(before, after, messages)
+}
+
+// verifyReword checks that each rewritten commit (after[i], formerly
+// before[i]) carries its replacement message, so a rebase that silently
+// skipped a commit is reported rather than claimed as a success. Messages are
+// committed with --cleanup=verbatim, so only surrounding whitespace differs.
+func (r *Repository) verifyReword(before, after []string, messages map[string]string) error {
+	for i, sha := range before {
+		message, ok := messages[sha]
+		if !ok {
+			continue
+		}
+		actual, err := r.GetCommitMessage(after[i])
+		if err != nil {
+			return err
+		}
+		if actual != strings.TrimSpace(message) {
+			return fmt.Errorf("the message of %s was not changed; check the branch history", shortSHA(sha))
+		}
+	}
+	return nil
+}
+
+// GetDefaultBranch returns the branch that feature branches are usually merged into
+func (r *Repository) GetDefaultBranch() (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+	cmd.Dir = r.workingDir
+	if output, err := cmd.Output(); err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	for _, candidate := range []string{"main", "master", "origin/main", "origin/master"} {
+		if _, err := r.ResolveRevision(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("could not determine the default branch, pass --base explicitly")
+}
+
+// GetMergeBase returns the best common ancestor of two revisions
+func (r *Repository) GetMergeBase(a, b string) (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "merge-base", a, b)
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no common ancestor between %s and %s", a, b)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetRangeDiff returns the combined structured diff between two revisions
+func (r *Repository) GetRangeDiff(from, to string) (*interfaces.Diff, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return nil, err
+	}
+
+	diff, err := r.structuredDiff("diff", from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff %s..%s: %v", from, to, err)
+	}
+	return diff, nil
+}
+
+// CreateBackupRef stores the current HEAD under refs/codegenius/backup/ and
+// returns the full ref name so it can be restored later
+func (r *Repository) CreateBackupRef(name string) (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	ref := fmt.Sprintf("%s%s/%d", backupRefPrefix, strings.ReplaceAll(name, " ", "-"), time.Now().Unix())
+	cmd := exec.Command("git", "update-ref", ref, "HEAD")
+	cmd.Dir = r.workingDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to create backup ref %s: %v: %s", ref, err, strings.TrimSpace(string(output)))
+	}
+	return ref, nil
+}
+
+// ResetSoft moves HEAD to the given revision while keeping the index and working tree
+func (r *Repository) ResetSoft(rev string) error {
+	if err := r.validateGitRepo(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", "reset", "--soft", rev)
+	cmd.Dir = r.workingDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset to %s: %v: %s", rev, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// oldestCommit returns the target commit furthest from HEAD, verifying that
+// every target is reachable from HEAD
+func (r *Repository) oldestCommit(messages map[string]string) (string, error) {
+	history, err := r.ListCommits("HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	for _, sha := range history {
+		if _, ok := messages[sha]; ok {
+			for target := range messages {
+				if !containsString(history, target) {
+					return "", fmt.Errorf("commit %s is not on the current branch", shortSHA(target))
+				}
+			}
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("none of the commits to reword are on the current branch")
+}
+
+// hasMerges reports whether a revision range contains merge commits
+func (r *Repository) hasMerges(revRange string) bool {
+	cmd := exec.Command("git", "rev-list", "--merges", revRange)
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
+
+// containsString reports whether a slice contains the given value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// shellQuote quotes a value for a POSIX shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// GetStagedPatch returns the staged changes as a patch that can be re-applied,
+// including binary contents
+func (r *Repository) GetStagedPatch() (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "diff", "--cached", "--binary", "--no-color", "--no-ext-diff")
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged patch: %v", err)
+	}
+	return string(output), nil
+}
+
+// CommitSeries creates one commit per patch on top of HEAD. Each patch is
+// applied with `git apply --cached` to a temporary index, so the real index is
+// left alone and anything not covered by the patches stays staged. If any
+// step fails, HEAD is moved back to where it started.
+func (r *Repository) CommitSeries(commits []interfaces.PatchCommit, opts interfaces.CommitOptions) error {
+	if err := r.validateGitRepo(); err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+
+	original, err := r.ResolveRevision("HEAD")
+	if err != nil {
+		return fmt.Errorf("splitting requires an existing commit to build on: %v", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "codegenius-split-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	indexEnv := append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(tmpDir, "index"))
+
+	readTree := exec.Command("git", "read-tree", "HEAD")
+	readTree.Dir = r.workingDir
+	readTree.Env = indexEnv
+	if output, err := readTree.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to prepare temporary index: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	for i, commit := range commits {
+		if err := r.commitPatch(commit, opts, indexEnv); err != nil {
+			if rollbackErr := r.ResetSoft(original); rollbackErr != nil {
+				return fmt.Errorf("commit %d of %d failed (%v) and rolling back failed: %v", i+1, len(commits), err, rollbackErr)
+			}
+			return fmt.Errorf("commit %d of %d failed, rolled back to %s: %v", i+1, len(commits), shortSHA(original), err)
+		}
+	}
+
+	return nil
+}
+
+// commitPatch applies a patch to the temporary index and commits it
+func (r *Repository) commitPatch(commit interfaces.PatchCommit, opts interfaces.CommitOptions, indexEnv []string) error {
+	// Patch paths are relative to the repository root
+	root, err := r.GetRepoRoot()
+	if err != nil {
+		return err
+	}
+
+	apply := exec.Command("git", "apply", "--cached", "--recount", "-")
+	apply.Dir = root
+	apply.Env = indexEnv
+	apply.Stdin = strings.NewReader(commit.Patch)
+	if output, err := apply.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to apply patch for %q: %v: %s", firstLine(commit.Message), err, strings.TrimSpace(string(output)))
+	}
+
+	args := append([]string{"commit"}, commitArgs(opts)...)
+	args = append(args, "-m", commit.Message)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.workingDir
+	cmd.Env = indexEnv
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to commit %q: %v", firstLine(commit.Message), err)
+	}
+	return nil
+}
+
+// firstLine returns the subject line of a commit message
+func firstLine(message string) string {
+	if index := strings.Index(message, "\n"); index != -1 {
+		return message[:index]
+	}
+	return message
+}
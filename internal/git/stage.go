@@ -0,0 +1,126 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// StatusEntry is one line of `git status --porcelain`
+type StatusEntry struct {
+	Index    byte   // status in the index (X)
+	WorkTree byte   // status in the working tree (Y)
+	Path     string // current path
+	OrigPath string // original path for renames and copies
+}
+
+// IsUntracked reports whether the file is not yet known to git
+func (e StatusEntry) IsUntracked() bool {
+	return e.Index == '?' && e.WorkTree == '?'
+}
+
+// HasUnstagedChanges reports whether the working tree differs from the index
+func (e StatusEntry) HasUnstagedChanges() bool {
+	return e.IsUntracked() || (e.WorkTree != ' ' && e.WorkTree != '!')
+}
+
+// Code returns the two-letter porcelain status code, e.g. " M" or "??"
+func (e StatusEntry) Code() string {
+	return string([]byte{e.Index, e.WorkTree})
+}
+
+// ParseStatus parses `git status --porcelain` (v1) output
+func ParseStatus(porcelain string) []StatusEntry {
+	var entries []StatusEntry
+	for _, line := range strings.Split(porcelain, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+
+		entry := StatusEntry{Index: line[0], WorkTree: line[1]}
+		path := line[3:]
+		if parts := strings.SplitN(path, " -> ", 2); len(parts) == 2 {
+			entry.OrigPath = unquotePath(parts[0])
+			path = parts[1]
+		}
+		entry.Path = unquotePath(path)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// unquotePath decodes the C-style quoting git applies to unusual paths
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
+
+// GetUnstagedDiff returns the diff between the working tree and the index,
+// including binary contents so binary changes can be staged as a patch
+func (r *Repository) GetUnstagedDiff() (string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "diff", "--binary", "--no-color", "--no-ext-diff")
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get unstaged diff: %v", err)
+	}
+	return string(output), nil
+}
+
+// StageFiles stages whole files, including untracked files and deletions
+func (r *Repository) StageFiles(paths []string) error {
+	if err := r.validateGitRepo(); err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	// Status paths are relative to the repository root, wherever codegenius runs
+	root, err := r.GetRepoRoot()
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"add", "--all", "--"}, paths...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage files: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// StagePatch applies a patch to the index only, staging individual hunks
+func (r *Repository) StagePatch(patch string) error {
+	if err := r.validateGitRepo(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(patch) == "" {
+		return nil
+	}
+
+	// Patch paths are relative to the repository root, and from a subdirectory
+	// git apply would skip every file outside it
+	root, err := r.GetRepoRoot()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", "apply", "--cached", "--recount", "-")
+	cmd.Dir = root
+	cmd.Stdin = strings.NewReader(patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage hunks: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
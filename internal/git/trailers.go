@@ -0,0 +1,167 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+const (
+	// SignedOffByKey is the trailer key used by `git commit --signoff`
+	SignedOffByKey = "Signed-off-by"
+	// CoAuthoredByKey is the trailer key recognised by GitHub and GitLab for co-authors
+	CoAuthoredByKey = "Co-authored-by"
+)
+
+// TrailerOptions describes which trailers should be appended to a commit message
+type TrailerOptions struct {
+	Signoff   bool
+	CoAuthors []string
+	Extra     []interfaces.Trailer
+}
+
+// ApplyTrailers appends trailers to a commit message using `git interpret-trailers`
+func (r *Repository) ApplyTrailers(message string, trailers []interfaces.Trailer) (string, error) {
+	if len(trailers) == 0 {
+		return message, nil
+	}
+
+	args := []string{"interpret-trailers", "--if-exists", "addIfDifferent"}
+	for _, trailer := range trailers {
+		args = append(args, "--trailer", fmt.Sprintf("%s: %s", trailer.Key, trailer.Value))
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.workingDir
+	// interpret-trailers only separates trailers from a body that ends in a newline
+	cmd.Stdin = strings.NewReader(strings.TrimRight(message, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to apply commit trailers: %v", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetSignoff returns the Signed-off-by trailer for the current committer identity
+func (r *Repository) GetSignoff() (interfaces.Trailer, error) {
+	cmd := exec.Command("git", "var", "GIT_COMMITTER_IDENT")
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return interfaces.Trailer{}, fmt.Errorf("failed to read committer identity: %v", err)
+	}
+
+	// The ident has the form "Name <email> <timestamp> <tz>"
+	ident := strings.TrimSpace(string(output))
+	if end := strings.LastIndex(ident, ">"); end != -1 {
+		ident = ident[:end+1]
+	}
+
+	return interfaces.Trailer{Key: SignedOffByKey, Value: ident}, nil
+}
+
+// GetRecentAuthors returns distinct "Name <email>" authors from the most recent commits
+func (r *Repository) GetRecentAuthors(limit int) ([]string, error) {
+	if err := r.validateGitRepo(); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 200
+	}
+
+	cmd := exec.Command("git", "log", fmt.Sprintf("-%d", limit), "--pretty=format:%an <%ae>")
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		// A repository without commits has no authors yet
+		return []string{}, nil
+	}
+
+	seen := make(map[string]bool)
+	authors := make([]string, 0)
+	for _, author := range strings.Split(string(output), "\n") {
+		author = strings.TrimSpace(author)
+		if author == "" || seen[strings.ToLower(author)] {
+			continue
+		}
+		seen[strings.ToLower(author)] = true
+		authors = append(authors, author)
+	}
+	return authors, nil
+}
+
+// ParseTrailer parses a "Key: Value" (or "Key=Value") string into a trailer
+func ParseTrailer(raw string) (interfaces.Trailer, error) {
+	separator := strings.IndexAny(raw, ":=")
+	if separator <= 0 {
+		return interfaces.Trailer{}, fmt.Errorf("invalid trailer %q, expected 'Key: Value'", raw)
+	}
+
+	key := strings.TrimSpace(raw[:separator])
+	value := strings.TrimSpace(raw[separator+1:])
+	if key == "" || value == "" || strings.ContainsAny(key, " \t") {
+		return interfaces.Trailer{}, fmt.Errorf("invalid trailer %q, expected 'Key: Value'", raw)
+	}
+
+	return interfaces.Trailer{Key: key, Value: value}, nil
+}
+
+// CoAuthorCandidates merges the configured team roster with recent git authors,
+// excluding the current committer
+func CoAuthorCandidates(repo interfaces.RepositoryInfo, team []string) []string {
+	self := ""
+	if signoff, err := repo.GetSignoff(); err == nil {
+		self = strings.ToLower(signoff.Value)
+	}
+
+	seen := map[string]bool{self: true}
+	candidates := make([]string, 0, len(team))
+	add := func(author string) {
+		author = strings.TrimSpace(author)
+		if author == "" || seen[strings.ToLower(author)] {
+			return
+		}
+		seen[strings.ToLower(author)] = true
+		candidates = append(candidates, author)
+	}
+
+	for _, member := range team {
+		add(member)
+	}
+	if recent, err := repo.GetRecentAuthors(0); err == nil {
+		for _, author := range recent {
+			add(author)
+		}
+	}
+	return candidates
+}
+
+// CollectTrailers combines configured trailers with per-commit options into
+// the final list of trailers, in the order they should appear
+func CollectTrailers(repo interfaces.GitRepository, commitConfig interfaces.CommitConfig, opts TrailerOptions) ([]interfaces.Trailer, error) {
+	trailers := make([]interfaces.Trailer, 0)
+
+	for _, author := range opts.CoAuthors {
+		if strings.TrimSpace(author) == "" {
+			continue
+		}
+		trailers = append(trailers, interfaces.Trailer{Key: CoAuthoredByKey, Value: strings.TrimSpace(author)})
+	}
+
+	trailers = append(trailers, commitConfig.Trailers...)
+	trailers = append(trailers, opts.Extra...)
+
+	if opts.Signoff || commitConfig.Signoff {
+		signoff, err := repo.GetSignoff()
+		if err != nil {
+			return nil, err
+		}
+		trailers = append(trailers, signoff)
+	}
+
+	return trailers, nil
+}
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// ExportFormats lists the formats Export can write
+var ExportFormats = []string{"csv", "jsonl", "md"}
+
+// exportColumns is the fixed column order of CSV exports; JSON Lines records
+// use the same keys in the same order
+var exportColumns = []string{
+	"timestamp", "repo", "branch", "sha", "author", "type", "scope", "subject",
+	"message", "files_changed", "insertions", "deletions", "source",
+}
+
+// exportRecord is one exported entry. Field order matches exportColumns.
+type exportRecord struct {
+	Timestamp    string `json:"timestamp"` // ISO 8601 in the commit's timezone
+	Repo         string `json:"repo"`
+	Branch       string `json:"branch"`
+	SHA          string `json:"sha"`
+	Author       string `json:"author"`
+	Type         string `json:"type"`
+	Scope        string `json:"scope"`
+	Subject      string `json:"subject"`
+	Message      string `json:"message"`
+	FilesChanged int    `json:"files_changed"`
+	Insertions   int    `json:"insertions"`
+	Deletions    int    `json:"deletions"`
+	Source       string `json:"source"`
+}
+
+// newExportRecord flattens an entry for export
+func newExportRecord(entry interfaces.HistoryEntry) exportRecord {
+	commitType, scope := ParseConventional(entry.Summary)
+	return exportRecord{
+		Timestamp:    entry.Timestamp.Format(time.RFC3339),
+		Repo:         entry.Repo,
+		Branch:       entry.Branch,
+		SHA:          entry.SHA,
+		Author:       entry.Author,
+		Type:         commitType,
+		Scope:        scope,
+		Subject:      firstLine(entry.Summary),
+		Message:      entry.Summary,
+		FilesChanged: entry.FilesChanged,
+		Insertions:   entry.Insertions,
+		Deletions:    entry.Deletions,
+		Source:       entry.Source,
+	}
+}
+
+// values returns the record's fields in exportColumns order
+func (r exportRecord) values() []string {
+	return []string{
+		r.Timestamp, r.Repo, r.Branch, r.SHA, r.Author, r.Type, r.Scope, r.Subject,
+		r.Message, strconv.Itoa(r.FilesChanged), strconv.Itoa(r.Insertions), strconv.Itoa(r.Deletions), r.Source,
+	}
+}
+
+// Export writes entries, oldest first, as CSV with a header row, JSON Lines
+// (one object per line) or a Markdown table
+func Export(w io.Writer, entries []interfaces.HistoryEntry, format string) error {
+	sorted := append([]interfaces.HistoryEntry{}, entries...)
+	sortByTime(sorted)
+
+	switch format {
+	case "csv":
+		return exportCSV(w, sorted)
+	case "jsonl":
+		return exportJSONL(w, sorted)
+	case "md":
+		return exportMarkdown(w, sorted)
+	default:
+		return fmt.Errorf("unknown export format %q (available: %s)", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+// exportCSV writes one row per entry under a header of exportColumns
+func exportCSV(w io.Writer, entries []interfaces.HistoryEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := writer.Write(newExportRecord(entry).values()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportJSONL writes one JSON object per line
+func exportJSONL(w io.Writer, entries []interfaces.HistoryEntry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, entry := range entries {
+		if err := encoder.Encode(newExportRecord(entry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportMarkdown writes a table of subjects; full messages are left to CSV and JSON Lines
+func exportMarkdown(w io.Writer, entries []interfaces.HistoryEntry) error {
+	var table strings.Builder
+	table.WriteString("| Timestamp | Repository | Branch | SHA | Type | Scope | Subject | Files | + | − |\n")
+	table.WriteString("|-----------|------------|--------|-----|------|-------|---------|------:|--:|--:|\n")
+	for _, entry := range entries {
+		record := newExportRecord(entry)
+		sha := record.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		table.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %d | %d | %d |\n",
+			record.Timestamp, markdownCell(repoName(record.Repo)), markdownCell(record.Branch), sha,
+			record.Type, markdownCell(record.Scope), markdownCell(record.Subject),
+			record.FilesChanged, record.Insertions, record.Deletions))
+	}
+	_, err := io.WriteString(w, table.String())
+	return err
+}
+
+// markdownCell escapes pipes so a value stays inside its table cell
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
@@ -1,9 +1,9 @@
 package history
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -11,8 +11,11 @@ import (
 	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
 )
 
+// WorkHistoryFile is the name of the history log inside a repository's git directory
+const WorkHistoryFile = "work_history.jsonl"
+
 const (
-	workHistoryFile   = ".git/work_history.json"
+	workHistoryFile   = ".git/" + WorkHistoryFile // used outside a repository, or when git cannot tell
 	dateFormat        = "02 Jan 2006"
 	displayDateFormat = "02 Jan 2006"
 )
@@ -24,97 +27,215 @@ type WorkHistory struct {
 
 // Manager implements the HistoryManager interface
 type Manager struct {
-	history  *WorkHistory
-	filePath string
+	history     *WorkHistory
+	filePath    string
+	centralPath string // user-wide store shared by every repository, "" when disabled
+	retention   interfaces.Retention
 }
 
-// NewManager creates a new history manager
-func NewManager(filePath string) interfaces.HistoryManager {
+// NewManager creates a new history manager. When centralPath is set, every
+// recorded commit is also written to that user-wide store. The retention
+// limits are applied whenever the history is written.
+func NewManager(filePath, centralPath string, retention interfaces.Retention) interfaces.HistoryManager {
 	if filePath == "" {
 		filePath = workHistoryFile
 	}
 	return &Manager{
-		filePath: filePath,
+		filePath:    filePath,
+		centralPath: centralPath,
+		retention:   retention,
 	}
 }
 
-// Load reads the work history from file or creates a new one
-func (m *Manager) Load() error {
-	history := &WorkHistory{
-		Entries: make([]interfaces.HistoryEntry, 0),
-	}
-
-	if _, err := os.Stat(m.filePath); os.IsNotExist(err) {
-		m.history = history
-		return nil // Return empty history if file doesn't exist
-	}
-
-	data, err := os.ReadFile(m.filePath)
-	if err != nil {
-		return fmt.Errorf("error reading work history file: %v", err)
+// CentralStorePath returns the user-wide history store,
+// $XDG_DATA_HOME/codegenius/history.jsonl (~/.local/share when unset)
+func CentralStorePath() string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "share")
 	}
+	return filepath.Join(dir, "codegenius", "history.jsonl")
+}
 
-	err = json.Unmarshal(data, history)
+// Load reads the work history from file or creates a new one
+func (m *Manager) Load() error {
+	entries, _, err := readLog(m.filePath)
 	if err != nil {
-		return fmt.Errorf("error parsing work history: %v", err)
+		return err
 	}
 
-	m.history = history
+	// Concurrent writers append in whatever order they finish
+	sortByTime(entries)
+	m.history = &WorkHistory{Entries: entries}
 	return nil
 }
 
-// Save writes the work history to file
+// Save rewrites the work history file with the loaded entries
 func (m *Manager) Save() error {
 	if m.history == nil {
 		return fmt.Errorf("no history data to save")
 	}
+	return writeLog(m.filePath, m.history.Entries)
+}
 
-	// Ensure the .git directory exists
-	dir := ".git"
-	if strings.Contains(m.filePath, "/") {
-		dir = m.filePath[:strings.LastIndex(m.filePath, "/")]
+// AddEntry adds a new entry to the work history. A missing timestamp
+// defaults to now. A commit that is already recorded (matched by SHA), e.g.
+// by a hook, is not added twice.
+func (m *Manager) AddEntry(entry interfaces.HistoryEntry) error {
+	if strings.TrimSpace(entry.Summary) == "" {
+		return fmt.Errorf("commit message cannot be empty")
 	}
 
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("error creating directory %s: %v", dir, err)
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
 	}
+	entry.Date = entry.Timestamp.Format(dateFormat)
+	if m.retention.SubjectOnly {
+		entry.Summary = firstLine(entry.Summary)
+	}
+	entry.ID = EntryID(entry)
 
-	data, err := json.MarshalIndent(m.history, "", "  ")
+	stored, added, err := appendLog(m.filePath, []interfaces.HistoryEntry{entry}, m.retention)
 	if err != nil {
-		return fmt.Errorf("error marshaling work history: %v", err)
+		return err
 	}
+	m.setEntries(stored)
+	return m.mirror(added)
+}
 
-	err = os.WriteFile(m.filePath, data, 0644)
-	if err != nil {
-		return fmt.Errorf("error writing work history file: %v", err)
+// RecordCommit adds the commit just made at HEAD, with its SHA, branch,
+// author, repository and diff stats. Details git cannot provide are left
+// empty rather than failing the record. When HEAD was amended, replaces is
+// the SHA of the commit it replaced, whose entry is removed.
+func (m *Manager) RecordCommit(repo interfaces.GitRepository, message, source, replaces string) error {
+	entry := interfaces.HistoryEntry{
+		Summary: message,
+		Source:  source,
 	}
 
+	if info, err := repo.GetCommitInfo("HEAD"); err == nil {
+		entry.SHA = info.SHA
+		entry.Author = info.Author
+		entry.Timestamp = info.Timestamp
+		entry.FilesChanged = info.FilesChanged
+		entry.Insertions = info.Insertions
+		entry.Deletions = info.Deletions
+	}
+	if branch, err := repo.GetCurrentBranch(); err == nil {
+		entry.Branch = branch
+	}
+	if root, err := repo.GetRepoRoot(); err == nil {
+		entry.Repo = root
+	}
+
+	if replaces != "" && replaces != entry.SHA {
+		if err := m.removeCommit(replaces); err != nil {
+			return err
+		}
+	}
+	return m.AddEntry(entry)
+}
+
+// removeCommit drops the entries of a commit (matched by full SHA) from the
+// repository history and the central store
+func (m *Manager) removeCommit(sha string) error {
+	paths := []string{m.filePath}
+	if m.centralPath != "" {
+		paths = append(paths, m.centralPath)
+	}
+
+	for _, path := range paths {
+		err := updateLog(path, func(entries []interfaces.HistoryEntry) ([]interfaces.HistoryEntry, bool, error) {
+			kept := make([]interfaces.HistoryEntry, 0, len(entries))
+			for _, entry := range entries {
+				if entry.SHA != sha {
+					kept = append(kept, entry)
+				}
+			}
+			return kept, len(kept) != len(entries), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// AddEntry adds a new entry to the work history
-func (m *Manager) AddEntry(message string) error {
-	if m.history == nil {
-		if err := m.Load(); err != nil {
-			return fmt.Errorf("failed to load history before adding entry: %v", err)
+// Import adds commits that are not in the history yet (matched by SHA) as
+// imported entries. It returns how many entries were added.
+func (m *Manager) Import(commits []interfaces.CommitInfo, repo string) (int, error) {
+	// git log lists newest first; add oldest first so equal timestamps keep commit order
+	var entries []interfaces.HistoryEntry
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		if strings.TrimSpace(commit.Message) == "" {
+			continue
+		}
+
+		summary := commit.Message
+		if m.retention.SubjectOnly {
+			summary = firstLine(summary)
 		}
+
+		entry := interfaces.HistoryEntry{
+			Date:         commit.Timestamp.Format(dateFormat),
+			Summary:      summary,
+			Timestamp:    commit.Timestamp,
+			SHA:          commit.SHA,
+			Author:       commit.Author,
+			Repo:         repo,
+			FilesChanged: commit.FilesChanged,
+			Insertions:   commit.Insertions,
+			Deletions:    commit.Deletions,
+			Source:       interfaces.SourceImported,
+		}
+		entry.ID = EntryID(entry)
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return 0, nil
 	}
 
-	if strings.TrimSpace(message) == "" {
-		return fmt.Errorf("commit message cannot be empty")
+	stored, added, err := appendLog(m.filePath, entries, m.retention)
+	if err != nil {
+		return 0, err
 	}
+	m.setEntries(stored)
+	return len(added), m.mirror(added)
+}
 
-	now := time.Now()
-	entry := interfaces.HistoryEntry{
-		Date:    now.Format(dateFormat),
-		Summary: message,
+// setEntries replaces the loaded history with entries read back from the file
+func (m *Manager) setEntries(entries []interfaces.HistoryEntry) {
+	sortByTime(entries)
+	m.history = &WorkHistory{Entries: entries}
+}
+
+// mirror adds entries to the central store, skipping commits it already
+// holds (matched by SHA). Only the age limit applies there: the entry limit
+// is per repository.
+func (m *Manager) mirror(entries []interfaces.HistoryEntry) error {
+	if m.centralPath == "" || len(entries) == 0 {
+		return nil
 	}
 
-	m.history.Entries = append(m.history.Entries, entry)
-	return m.Save()
+	// Unlike a repository's git directory, the user-wide store may need creating
+	if err := os.MkdirAll(filepath.Dir(m.centralPath), 0755); err != nil {
+		return fmt.Errorf("error updating central history: %v", err)
+	}
+
+	retention := interfaces.Retention{MaxAge: m.retention.MaxAge}
+	if _, _, err := appendLog(m.centralPath, entries, retention); err != nil {
+		return fmt.Errorf("error updating central history: %v", err)
+	}
+	return nil
 }
 
-// Display shows work history for a specific month/year
+// Display shows work history for a month ("Dec 2024"), year or ISO week, or
+// a summary of every month when monthYear is empty
 func (m *Manager) Display(monthYear string) error {
 	if m.history == nil {
 		if err := m.Load(); err != nil {
@@ -127,40 +248,122 @@ func (m *Manager) Display(monthYear string) error {
 		return m.displayAll()
 	}
 
-	// Filter entries by month/year
-	filtered := m.FilterByMonthYear(monthYear)
-	if len(filtered) == 0 {
-		fmt.Printf("No work history found for %s\n", monthYear)
-		return nil
+	entries, err := m.Query(interfaces.HistoryQuery{Period: monthYear})
+	if err != nil {
+		return err
+	}
+	m.DisplayEntries("Work History for "+monthYear, entries, false)
+	return nil
+}
+
+// DisplayAcrossRepos shows the central store's history for a month/year (or
+// all of it), with a per-repository breakdown before the day-by-day list
+func (m *Manager) DisplayAcrossRepos(monthYear string) error {
+	entries, err := m.Query(interfaces.HistoryQuery{Period: monthYear, AllRepos: true})
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("\n🗓️  Work History for %s\n", monthYear)
+	title := "Complete Work History"
+	if monthYear != "" {
+		title = "Work History for " + monthYear
+	}
+	m.DisplayEntries(title+" across repositories", entries, true)
+	return nil
+}
+
+// DisplayQuery shows the entries matching a query, with a per-repository
+// breakdown when it spans the central store
+func (m *Manager) DisplayQuery(q interfaces.HistoryQuery) error {
+	entries, err := m.Query(q)
+	if err != nil {
+		return err
+	}
+
+	title := "Work History"
+	if description := describeQuery(q); description != "" {
+		title += " (" + description + ")"
+	}
+	if q.AllRepos {
+		title += " across repositories"
+	}
+	m.DisplayEntries(title, entries, q.AllRepos)
+	return nil
+}
+
+// DisplayEntries prints entries day by day, oldest first. With byRepo set, a
+// per-repository breakdown comes first and each line names its repository.
+func (m *Manager) DisplayEntries(title string, entries []interfaces.HistoryEntry, byRepo bool) {
+	if len(entries) == 0 {
+		fmt.Println("No work history found.")
+		return
+	}
+
+	fmt.Printf("\n🗓️  %s\n", title)
 	fmt.Println(strings.Repeat("=", 50))
 
-	// Group by date
-	dateGroups := make(map[string][]interfaces.HistoryEntry)
-	for _, entry := range filtered {
-		dateGroups[entry.Date] = append(dateGroups[entry.Date], entry)
+	repos := repoBreakdown(entries)
+	if byRepo {
+		fmt.Println("\n📦 Repositories")
+		for _, totals := range repos {
+			fmt.Printf("   %-24s %4d commits  +%d −%d\n", totals.name, totals.commits, totals.insertions, totals.deletions)
+		}
 	}
 
-	// Sort dates
-	dates := make([]string, 0, len(dateGroups))
-	for date := range dateGroups {
-		dates = append(dates, date)
+	sorted := append([]interfaces.HistoryEntry{}, entries...)
+	sortByTime(sorted)
+	currentDate, number := "", 0
+	for _, entry := range sorted {
+		if entry.Date != currentDate {
+			currentDate, number = entry.Date, 0
+			fmt.Printf("\n📅 %s\n", currentDate)
+		}
+		number++
+		// The ID lets a single entry be removed with 'codegenius history delete'
+		if byRepo {
+			fmt.Printf("   %d. %s [%s] %s\n", number, entry.ID, repoName(entry.Repo), firstLine(entry.Summary))
+		} else {
+			fmt.Printf("   %d. %s %s\n", number, entry.ID, firstLine(entry.Summary))
+		}
 	}
-	sort.Strings(dates)
 
-	// Display entries
-	for _, date := range dates {
-		entries := dateGroups[date]
-		fmt.Printf("\n📅 %s\n", date)
-		for i, entry := range entries {
-			fmt.Printf("   %d. %s\n", i+1, entry.Summary)
+	if byRepo {
+		fmt.Printf("\nTotal commits: %d across %d repositories\n", len(entries), len(repos))
+	} else {
+		fmt.Printf("\nTotal commits: %d\n", len(entries))
+	}
+}
+
+// repoTotals summarises one repository's entries
+type repoTotals struct {
+	name                           string
+	commits, insertions, deletions int
+}
+
+// repoBreakdown totals entries per repository, busiest first
+func repoBreakdown(entries []interfaces.HistoryEntry) []*repoTotals {
+	byRepo := make(map[string]*repoTotals)
+	for _, entry := range entries {
+		name := repoName(entry.Repo)
+		if byRepo[name] == nil {
+			byRepo[name] = &repoTotals{name: name}
 		}
+		byRepo[name].commits++
+		byRepo[name].insertions += entry.Insertions
+		byRepo[name].deletions += entry.Deletions
 	}
 
-	fmt.Printf("\nTotal commits: %d\n", len(filtered))
-	return nil
+	repos := make([]*repoTotals, 0, len(byRepo))
+	for _, totals := range byRepo {
+		repos = append(repos, totals)
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		if repos[i].commits != repos[j].commits {
+			return repos[i].commits > repos[j].commits
+		}
+		return repos[i].name < repos[j].name
+	})
+	return repos
 }
 
 // displayAll shows all work history entries
@@ -207,46 +410,26 @@ func (m *Manager) displayAll() error {
 	return nil
 }
 
-// FilterByMonthYear filters entries by month/year string
+// FilterByMonthYear returns the entries in a month ("Dec 2024"), year or
+// ISO week, or none when the period cannot be parsed
 func (m *Manager) FilterByMonthYear(monthYear string) []interfaces.HistoryEntry {
 	if m.history == nil {
 		return []interfaces.HistoryEntry{}
 	}
 
-	var filtered []interfaces.HistoryEntry
-	for _, entry := range m.history.Entries {
-		if strings.Contains(entry.Date, monthYear) {
-			filtered = append(filtered, entry)
-		}
+	start, end, err := ParsePeriod(monthYear)
+	if err != nil {
+		return []interfaces.HistoryEntry{}
 	}
-	return filtered
+	return m.GetEntriesInDateRange(start, end)
 }
 
 // GetStats returns statistics about the work history
-func (m *Manager) GetStats() map[string]interface{} {
+func (m *Manager) GetStats() interfaces.HistoryStats {
 	if m.history == nil {
-		return map[string]interface{}{
-			"total_commits":     0,
-			"monthly_breakdown": map[string]int{},
-			"most_active_month": "",
-		}
-	}
-
-	stats := make(map[string]interface{})
-
-	stats["total_commits"] = len(m.history.Entries)
-
-	// Group by month for monthly stats
-	monthGroups := make(map[string]int)
-	for _, entry := range m.history.Entries {
-		monthYear := extractMonthYear(entry.Date)
-		monthGroups[monthYear]++
+		return interfaces.HistoryStats{}
 	}
-
-	stats["monthly_breakdown"] = monthGroups
-	stats["most_active_month"] = findMostActiveMonth(monthGroups)
-
-	return stats
+	return computeStats(m.history.Entries, time.Now())
 }
 
 // GetHistory returns the full history (for advanced usage)
@@ -277,7 +460,8 @@ func (m *Manager) GetEntriesForDate(date string) []interfaces.HistoryEntry {
 	return entries
 }
 
-// GetEntriesInDateRange returns entries within a date range
+// GetEntriesInDateRange returns entries recorded between startDate and
+// endDate, inclusive
 func (m *Manager) GetEntriesInDateRange(startDate, endDate time.Time) []interfaces.HistoryEntry {
 	if m.history == nil {
 		return []interfaces.HistoryEntry{}
@@ -285,13 +469,11 @@ func (m *Manager) GetEntriesInDateRange(startDate, endDate time.Time) []interfac
 
 	var entries []interfaces.HistoryEntry
 	for _, entry := range m.history.Entries {
-		entryDate, err := time.Parse(dateFormat, entry.Date)
-		if err != nil {
+		if entry.Timestamp.IsZero() {
 			continue // Skip entries with invalid dates
 		}
 
-		if (entryDate.Equal(startDate) || entryDate.After(startDate)) &&
-			(entryDate.Equal(endDate) || entryDate.Before(endDate)) {
+		if !entry.Timestamp.Before(startDate) && !entry.Timestamp.After(endDate) {
 			entries = append(entries, entry)
 		}
 	}
@@ -306,6 +488,46 @@ func (m *Manager) Clear() error {
 	return m.Save()
 }
 
+// sortByTime orders entries chronologically, keeping the order of equal times
+func sortByTime(entries []interfaces.HistoryEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+}
+
+// repoName labels an entry's repository by its directory name
+func repoName(repo string) string {
+	if repo == "" {
+		return "unknown"
+	}
+	return filepath.Base(repo)
+}
+
+// firstLine returns the subject line of a commit message
+func firstLine(message string) string {
+	if index := strings.Index(message, "\n"); index != -1 {
+		return message[:index]
+	}
+	return message
+}
+
+// upgradeEntry fills the timestamp of entries recorded before full
+// timestamps were stored, using midnight local time on their date, and the
+// ID of entries recorded before IDs were stored
+func upgradeEntry(entry *interfaces.HistoryEntry) {
+	if entry.Timestamp.IsZero() {
+		if date, err := time.ParseInLocation(dateFormat, entry.Date, time.Local); err == nil {
+			entry.Timestamp = date
+		}
+	}
+	if entry.Date == "" && !entry.Timestamp.IsZero() {
+		entry.Date = entry.Timestamp.Format(dateFormat)
+	}
+	if entry.ID == "" {
+		entry.ID = EntryID(*entry)
+	}
+}
+
 // extractMonthYear extracts month/year from a date string
 func extractMonthYear(dateStr string) string {
 	// Parse the date and extract month/year
@@ -328,26 +550,11 @@ func truncateSummary(summary string, maxLen int) string {
 	return summary[:maxLen-3] + "..."
 }
 
-// findMostActiveMonth finds the month with the most commits
-func findMostActiveMonth(monthGroups map[string]int) string {
-	maxCommits := 0
-	mostActive := ""
-
-	for month, commits := range monthGroups {
-		if commits > maxCommits {
-			maxCommits = commits
-			mostActive = month
-		}
-	}
-
-	return mostActive
-}
-
 // Backward compatibility functions
 
 // Load reads the work history from file or creates a new one (legacy function)
 func Load() (*WorkHistory, error) {
-	manager := NewManager(workHistoryFile)
+	manager := NewManager(workHistoryFile, "", interfaces.Retention{})
 	err := manager.Load()
 	if err != nil {
 		return nil, err
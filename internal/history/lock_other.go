@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package history
+
+import "os"
+
+// lockFile is a no-op where the platform has no advisory file locks
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op where the platform has no advisory file locks
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,292 @@
+package history
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+var (
+	// conventionalRegex captures the type and optional scope of a conventional commit subject
+	conventionalRegex = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?!?:`)
+
+	// relativeRegex matches "3 days ago", "1 week ago" and friends
+	relativeRegex = regexp.MustCompile(`^(\d+)\s+(day|week|month|year)s?\s+ago$`)
+
+	// isoWeekRegex matches ISO weeks such as 2024-W50
+	isoWeekRegex = regexp.MustCompile(`^(\d{4})-?[Ww](\d{1,2})$`)
+)
+
+// dayLayouts are the absolute date formats accepted for --since and --until
+var dayLayouts = []string{"2006-01-02", dateFormat, "Jan 2 2006", "January 2 2006", "2006/01/02"}
+
+// timeLayouts are the absolute date-time formats, which are used as exact bounds
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02 15:04:05"}
+
+// monthLayouts are the formats accepted for a month period
+var monthLayouts = []string{"Jan 2006", "January 2006", "2006-01", "01/2006"}
+
+// ParseConventional returns the type and scope of a conventional commit
+// message, or empty strings when the subject does not follow the convention
+func ParseConventional(message string) (commitType, scope string) {
+	match := conventionalRegex.FindStringSubmatch(firstLine(message))
+	if match == nil {
+		return "", ""
+	}
+	return strings.ToLower(match[1]), match[2]
+}
+
+// Query returns the entries matching every filter of q, oldest first. With
+// AllRepos set the central store is searched instead of this repository.
+func (m *Manager) Query(q interfaces.HistoryQuery) ([]interfaces.HistoryEntry, error) {
+	source := m
+	if q.AllRepos {
+		if m.centralPath == "" {
+			return nil, fmt.Errorf("the central history store is disabled; enable it with 'codegenius config set --global history.central_store true'")
+		}
+		source = &Manager{filePath: m.centralPath}
+	}
+	if source.history == nil {
+		if err := source.Load(); err != nil {
+			return nil, fmt.Errorf("failed to load history: %v", err)
+		}
+	}
+
+	start, end, err := queryRange(q, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []interfaces.HistoryEntry
+	for _, entry := range source.GetEntriesInDateRange(start, end) {
+		if matchesQuery(entry, q) {
+			matches = append(matches, entry)
+		}
+	}
+	sortByTime(matches)
+	return matches, nil
+}
+
+// queryRange resolves the period and since/until bounds of a query into one
+// inclusive range. Unset bounds are left open.
+func queryRange(q interfaces.HistoryQuery, now time.Time) (time.Time, time.Time, error) {
+	start := time.Time{}
+	end := time.Date(9999, 12, 31, 23, 59, 59, 0, time.Local)
+
+	if q.Period != "" {
+		periodStart, periodEnd, err := ParsePeriod(q.Period)
+		if err != nil {
+			return start, end, err
+		}
+		start, end = periodStart, periodEnd
+	}
+	if q.Since != "" {
+		since, err := ParseDate(q.Since, now, false)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid --since: %v", err)
+		}
+		if since.After(start) {
+			start = since
+		}
+	}
+	if q.Until != "" {
+		until, err := ParseDate(q.Until, now, true)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid --until: %v", err)
+		}
+		if until.Before(end) {
+			end = until
+		}
+	}
+	return start, end, nil
+}
+
+// matchesQuery applies the non-date filters of a query to an entry
+func matchesQuery(entry interfaces.HistoryEntry, q interfaces.HistoryQuery) bool {
+	if q.Author != "" && !containsFold(entry.Author, q.Author) {
+		return false
+	}
+	if q.Repo != "" && !strings.EqualFold(repoName(entry.Repo), q.Repo) && !containsFold(entry.Repo, q.Repo) {
+		return false
+	}
+	if q.Branch != "" && !strings.EqualFold(entry.Branch, q.Branch) {
+		return false
+	}
+	if q.Type != "" || q.Scope != "" {
+		commitType, scope := ParseConventional(entry.Summary)
+		if q.Type != "" && !strings.EqualFold(commitType, q.Type) {
+			return false
+		}
+		if q.Scope != "" && !strings.EqualFold(scope, q.Scope) {
+			return false
+		}
+	}
+	if q.Text != "" && !containsFold(entry.Summary, q.Text) {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether s contains substr, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// ParseDate resolves a natural or absolute date relative to now. Day
+// expressions ("today", "yesterday", "monday", "3 days ago", "last week",
+// "2024-12-01") resolve to the start of that day, or its end when end is
+// set, so that --until includes the whole day. Date-times are exact.
+func ParseDate(value string, now time.Time, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return parsed, nil
+		}
+	}
+	value = strings.ToLower(value)
+
+	day, err := parseDay(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, now.Location())
+	if end {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+// parseDay resolves a day expression to any time on that day
+func parseDay(value string, now time.Time) (time.Time, error) {
+	switch value {
+	case "now", "today":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	case "last week":
+		return now.AddDate(0, 0, -7), nil
+	case "last month":
+		return now.AddDate(0, -1, 0), nil
+	case "last year":
+		return now.AddDate(-1, 0, 0), nil
+	case "this week":
+		return startOfWeek(now), nil
+	case "this month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), nil
+	case "this year":
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location()), nil
+	}
+
+	if match := relativeRegex.FindStringSubmatch(value); match != nil {
+		count, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "day":
+			return now.AddDate(0, 0, -count), nil
+		case "week":
+			return now.AddDate(0, 0, -7*count), nil
+		case "month":
+			return now.AddDate(0, -count, 0), nil
+		default:
+			return now.AddDate(-count, 0, 0), nil
+		}
+	}
+
+	// A weekday means its most recent occurrence, today included
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if value == name || value == "last "+name {
+			days := (int(now.Weekday()) - int(weekday) + 7) % 7
+			if days == 0 && value != name {
+				days = 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+
+	for _, layout := range dayLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return parsed, nil
+		}
+		// Month names are matched case-insensitively by title-casing them
+		if parsed, err := time.ParseInLocation(layout, titleCase(value), now.Location()); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised date %q (try 2024-12-01, yesterday, \"3 days ago\" or \"last week\")", value)
+}
+
+// ParsePeriod resolves a month ("Dec 2024", "2024-12"), a year ("2024") or an
+// ISO week ("2024-W50") to its first and last instant in local time
+func ParsePeriod(value string) (time.Time, time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if match := isoWeekRegex.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		week, _ := strconv.Atoi(match[2])
+		if week < 1 || week > 53 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid ISO week %q", value)
+		}
+		// Week 1 is the week containing January 4th
+		start := startOfWeek(time.Date(year, 1, 4, 0, 0, 0, 0, time.Local)).AddDate(0, 0, 7*(week-1))
+		if isoYear, _ := start.ISOWeek(); isoYear != year {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid ISO week %q", value)
+		}
+		return start, start.AddDate(0, 0, 7).Add(-time.Nanosecond), nil
+	}
+
+	if year, err := strconv.Atoi(value); err == nil && len(value) == 4 {
+		start := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(1, 0, 0).Add(-time.Nanosecond), nil
+	}
+
+	for _, layout := range monthLayouts {
+		if parsed, err := time.ParseInLocation(layout, titleCase(value), time.Local); err == nil {
+			return parsed, parsed.AddDate(0, 1, 0).Add(-time.Nanosecond), nil
+		}
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("unrecognised period %q (try \"Dec 2024\", 2024-12, 2024 or 2024-W50)", value)
+}
+
+// startOfWeek returns midnight on the Monday of t's ISO week
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	day := t.AddDate(0, 0, -offset)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// titleCase capitalises the first letter of each word, e.g. "dec 2024" to "Dec 2024"
+func titleCase(value string) string {
+	words := strings.Fields(strings.ToLower(value))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// describeQuery summarises the filters of a query for display titles
+func describeQuery(q interfaces.HistoryQuery) string {
+	var parts []string
+	add := func(label, value string) {
+		if value != "" {
+			parts = append(parts, label+value)
+		}
+	}
+	add("", q.Period)
+	add("since ", q.Since)
+	add("until ", q.Until)
+	add("type ", q.Type)
+	add("scope ", q.Scope)
+	add("author ", q.Author)
+	add("repo ", q.Repo)
+	add("branch ", q.Branch)
+	if q.Text != "" {
+		parts = append(parts, fmt.Sprintf("matching %q", q.Text))
+	}
+	return strings.Join(parts, ", ")
+}
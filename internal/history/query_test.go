@@ -0,0 +1,98 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+const testTimeLayout = "2006-01-02 15:04:05.999999999"
+
+func TestParseDate(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2024, 12, 18, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		end     bool
+		want    string
+		wantErr bool
+	}{
+		{value: "today", want: "2024-12-18 00:00:00"},
+		{value: "today", end: true, want: "2024-12-18 23:59:59.999999999"},
+		{value: "Yesterday", want: "2024-12-17 00:00:00"},
+		{value: "3 days ago", want: "2024-12-15 00:00:00"},
+		{value: "1 week ago", want: "2024-12-11 00:00:00"},
+		{value: "2 months ago", want: "2024-10-18 00:00:00"},
+		{value: "last week", want: "2024-12-11 00:00:00"},
+		{value: "this week", want: "2024-12-16 00:00:00"},
+		{value: "this month", want: "2024-12-01 00:00:00"},
+		{value: "monday", want: "2024-12-16 00:00:00"},
+		{value: "wednesday", want: "2024-12-18 00:00:00"},
+		{value: "last wednesday", want: "2024-12-11 00:00:00"},
+		{value: "Friday", want: "2024-12-13 00:00:00"},
+		{value: "2024-12-01", want: "2024-12-01 00:00:00"},
+		{value: "2024-12-01", end: true, want: "2024-12-01 23:59:59.999999999"},
+		{value: "dec 1 2024", want: "2024-12-01 00:00:00"},
+		{value: "01 Dec 2024", want: "2024-12-01 00:00:00"},
+		{value: "2024/12/01", want: "2024-12-01 00:00:00"},
+		{value: "2024-12-01T09:15", want: "2024-12-01 09:15:00"},
+		{value: "2024-12-01 09:15", end: true, want: "2024-12-01 09:15:00"},
+		{value: "someday", wantErr: true},
+		{value: "2024-13-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDate(tt.value, now, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if formatted := got.Format(testTimeLayout); formatted != tt.want {
+				t.Errorf("ParseDate(%q, end=%v) = %s, want %s", tt.value, tt.end, formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{value: "Dec 2024", wantStart: "2024-12-01 00:00:00", wantEnd: "2024-12-31 23:59:59.999999999"},
+		{value: "dec 2024", wantStart: "2024-12-01 00:00:00", wantEnd: "2024-12-31 23:59:59.999999999"},
+		{value: "February 2024", wantStart: "2024-02-01 00:00:00", wantEnd: "2024-02-29 23:59:59.999999999"},
+		{value: "2024-12", wantStart: "2024-12-01 00:00:00", wantEnd: "2024-12-31 23:59:59.999999999"},
+		{value: "12/2024", wantStart: "2024-12-01 00:00:00", wantEnd: "2024-12-31 23:59:59.999999999"},
+		{value: "2024", wantStart: "2024-01-01 00:00:00", wantEnd: "2024-12-31 23:59:59.999999999"},
+		{value: "2024-W50", wantStart: "2024-12-09 00:00:00", wantEnd: "2024-12-15 23:59:59.999999999"},
+		{value: "2025-w1", wantStart: "2024-12-30 00:00:00", wantEnd: "2025-01-05 23:59:59.999999999"},
+		{value: "2020-W53", wantStart: "2020-12-28 00:00:00", wantEnd: "2021-01-03 23:59:59.999999999"},
+		{value: "2024-W53", wantErr: true},
+		{value: "2024-W00", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			start, end, err := ParsePeriod(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriod(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := start.Format(testTimeLayout); got != tt.wantStart {
+				t.Errorf("ParsePeriod(%q) start = %s, want %s", tt.value, got, tt.wantStart)
+			}
+			if got := end.Format(testTimeLayout); got != tt.wantEnd {
+				t.Errorf("ParsePeriod(%q) end = %s, want %s", tt.value, got, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package history
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// minRefLength is the shortest ID or SHA prefix accepted when deleting entries
+const minRefLength = 4
+
+// EntryID derives the short identifier of an entry from what it records, so
+// entries written before IDs existed get the same ID every time they load
+func EntryID(entry interfaces.HistoryEntry) string {
+	hash := sha1.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%s", entry.Timestamp.UTC().Format(time.RFC3339Nano), entry.Repo, entry.SHA, entry.Summary)
+	return hex.EncodeToString(hash.Sum(nil))[:8]
+}
+
+// applyRetention drops entries older than the maximum age and all but the
+// newest MaxEntries of each repository, and strips commit bodies when only subjects are kept.
+// It returns the kept entries in their original order.
+func applyRetention(entries []interfaces.HistoryEntry, retention interfaces.Retention, now time.Time) (kept []interfaces.HistoryEntry, removed, trimmed int) {
+	keep := make([]bool, len(entries))
+	for i, entry := range entries {
+		keep[i] = retention.MaxAge <= 0 || entry.Timestamp.IsZero() || !entry.Timestamp.Before(now.Add(-retention.MaxAge))
+	}
+
+	if retention.MaxEntries > 0 {
+		// Newest first, so the oldest are dropped beyond the limit
+		order := make([]int, len(entries))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return entries[order[a]].Timestamp.After(entries[order[b]].Timestamp)
+		})
+
+		// The limit is per repository, so a busy project in the central store
+		// cannot evict the others
+		counts := make(map[string]int)
+		for _, i := range order {
+			if !keep[i] {
+				continue
+			}
+			counts[entries[i].Repo]++
+			keep[i] = counts[entries[i].Repo] <= retention.MaxEntries
+		}
+	}
+
+	kept = make([]interfaces.HistoryEntry, 0, len(entries))
+	for i, entry := range entries {
+		if !keep[i] {
+			removed++
+			continue
+		}
+		if retention.SubjectOnly && firstLine(entry.Summary) != entry.Summary {
+			entry.Summary = firstLine(entry.Summary)
+			trimmed++
+		}
+		kept = append(kept, entry)
+	}
+	return kept, removed, trimmed
+}
+
+// matchesRef reports whether ref is a prefix of the entry's ID or commit SHA
+func matchesRef(entry interfaces.HistoryEntry, ref string) bool {
+	ref = strings.ToLower(ref)
+	return strings.HasPrefix(entry.ID, ref) || (entry.SHA != "" && strings.HasPrefix(entry.SHA, ref))
+}
+
+// sameRepo reports whether an entry's repository is repo, given as a path or,
+// without a separator, as the repository's directory name
+func sameRepo(entryRepo, repo string) bool {
+	if entryRepo == "" {
+		return false
+	}
+	if !strings.ContainsRune(repo, filepath.Separator) && !strings.Contains(repo, "/") {
+		return filepath.Base(entryRepo) == repo
+	}
+	return filepath.Clean(entryRepo) == filepath.Clean(repo)
+}
+
+// Prune applies the retention limits to the repository history, or to the
+// central store with allRepos, where MaxEntries applies to each repository. With dryRun nothing is written. It returns
+// how many entries were removed and how many were trimmed to their subject.
+func (m *Manager) Prune(retention interfaces.Retention, allRepos, dryRun bool) (removed, trimmed int, err error) {
+	path := m.filePath
+	if allRepos {
+		if m.centralPath == "" {
+			return 0, 0, fmt.Errorf("the central history store is not enabled (set history.central_store to true)")
+		}
+		path = m.centralPath
+	}
+
+	err = updateLog(path, func(entries []interfaces.HistoryEntry) ([]interfaces.HistoryEntry, bool, error) {
+		var kept []interfaces.HistoryEntry
+		kept, removed, trimmed = applyRetention(entries, retention, time.Now())
+		return kept, !dryRun && (removed > 0 || trimmed > 0), nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return removed, trimmed, m.reload()
+}
+
+// Delete removes the entries matching each ref, an entry ID or a commit SHA
+// (or a prefix of either), from the repository history and the central
+// store. It fails without deleting anything when a ref matches nothing or
+// more than one entry.
+func (m *Manager) Delete(refs []string) ([]interfaces.HistoryEntry, error) {
+	for _, ref := range refs {
+		if len(ref) < minRefLength {
+			return nil, fmt.Errorf("%q is too short, use at least %d characters of an ID or SHA", ref, minRefLength)
+		}
+	}
+
+	paths := []string{m.filePath}
+	if m.centralPath != "" {
+		paths = append(paths, m.centralPath)
+	}
+
+	// Find every ref first, so a typo does not leave a partial delete behind
+	found := make(map[string]bool)
+	for _, path := range paths {
+		entries, _, err := readLog(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			ids := make(map[string]bool)
+			for _, entry := range entries {
+				if matchesRef(entry, ref) {
+					ids[entry.ID] = true
+				}
+			}
+			if len(ids) > 1 {
+				return nil, fmt.Errorf("%q matches %d entries, use more characters", ref, len(ids))
+			}
+			if len(ids) == 1 {
+				found[ref] = true
+			}
+		}
+	}
+	for _, ref := range refs {
+		if !found[ref] {
+			return nil, fmt.Errorf("no history entry matches %q", ref)
+		}
+	}
+
+	var deleted []interfaces.HistoryEntry
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		err := updateLog(path, func(entries []interfaces.HistoryEntry) ([]interfaces.HistoryEntry, bool, error) {
+			kept := make([]interfaces.HistoryEntry, 0, len(entries))
+			for _, entry := range entries {
+				matched := false
+				for _, ref := range refs {
+					matched = matched || matchesRef(entry, ref)
+				}
+				if !matched {
+					kept = append(kept, entry)
+					continue
+				}
+				if !seen[entry.ID] {
+					seen[entry.ID] = true
+					deleted = append(deleted, entry)
+				}
+			}
+			return kept, len(kept) != len(entries), nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, m.reload()
+}
+
+// Purge removes every entry recorded for repo, a path or directory name,
+// from the repository history and the central store. With clearLocal the
+// repository history is emptied entirely, including entries that predate
+// repository paths being recorded. It returns how many entries were removed.
+func (m *Manager) Purge(repo string, clearLocal bool) (int, error) {
+	paths := []string{m.filePath}
+	if m.centralPath != "" {
+		paths = append(paths, m.centralPath)
+	}
+
+	removed := 0
+	for _, path := range paths {
+		local := path == m.filePath
+		err := updateLog(path, func(entries []interfaces.HistoryEntry) ([]interfaces.HistoryEntry, bool, error) {
+			kept := make([]interfaces.HistoryEntry, 0, len(entries))
+			for _, entry := range entries {
+				if (local && clearLocal) || sameRepo(entry.Repo, repo) {
+					removed++
+					continue
+				}
+				kept = append(kept, entry)
+			}
+			return kept, len(kept) != len(entries), nil
+		})
+		if err != nil {
+			return removed, err
+		}
+	}
+	return removed, m.reload()
+}
+
+// reload refreshes the loaded history after the file was rewritten
+func (m *Manager) reload() error {
+	if m.history == nil {
+		return nil
+	}
+	return m.Load()
+}
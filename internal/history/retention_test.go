@@ -0,0 +1,165 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+func TestApplyRetention(t *testing.T) {
+	now := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 12, d, 10, 0, 0, 0, time.UTC) }
+	entries := []interfaces.HistoryEntry{
+		{SHA: "a1", Repo: "/src/api", Timestamp: day(1), Summary: "feat: a1\n\nbody"},
+		{SHA: "b1", Repo: "/src/web", Timestamp: day(2), Summary: "feat: b1"},
+		{SHA: "a2", Repo: "/src/api", Timestamp: day(20), Summary: "fix: a2"},
+		{SHA: "a3", Repo: "/src/api", Timestamp: day(30), Summary: "fix: a3\n\nbody"},
+		{SHA: "legacy", Summary: "chore: no timestamp"},
+	}
+
+	tests := []struct {
+		name        string
+		retention   interfaces.Retention
+		wantSHAs    []string
+		wantRemoved int
+		wantTrimmed int
+	}{
+		{
+			name:     "no limits",
+			wantSHAs: []string{"a1", "b1", "a2", "a3", "legacy"},
+		},
+		{
+			name:        "max entries applies per repository",
+			retention:   interfaces.Retention{MaxEntries: 1},
+			wantSHAs:    []string{"b1", "a3", "legacy"},
+			wantRemoved: 2,
+		},
+		{
+			name:        "max age keeps entries without a timestamp",
+			retention:   interfaces.Retention{MaxAge: 14 * 24 * time.Hour},
+			wantSHAs:    []string{"a2", "a3", "legacy"},
+			wantRemoved: 2,
+		},
+		{
+			name:        "age is applied before the entry limit",
+			retention:   interfaces.Retention{MaxAge: 14 * 24 * time.Hour, MaxEntries: 2},
+			wantSHAs:    []string{"a2", "a3", "legacy"},
+			wantRemoved: 2,
+		},
+		{
+			name:        "subject only trims bodies",
+			retention:   interfaces.Retention{SubjectOnly: true},
+			wantSHAs:    []string{"a1", "b1", "a2", "a3", "legacy"},
+			wantTrimmed: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, removed, trimmed := applyRetention(entries, tt.retention, now)
+			if got := shas(kept); !reflect.DeepEqual(got, tt.wantSHAs) {
+				t.Errorf("applyRetention() kept = %v, want %v", got, tt.wantSHAs)
+			}
+			if removed != tt.wantRemoved || trimmed != tt.wantTrimmed {
+				t.Errorf("applyRetention() removed, trimmed = %d, %d, want %d, %d", removed, trimmed, tt.wantRemoved, tt.wantTrimmed)
+			}
+			for _, entry := range kept {
+				if tt.retention.SubjectOnly && strings.Contains(entry.Summary, "\n") {
+					t.Errorf("applyRetention() kept body of %s", entry.SHA)
+				}
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	// IDs are fixed so that prefixes are predictable
+	entries := []interfaces.HistoryEntry{
+		{ID: "abcd1111", SHA: "f00d0001", Summary: "feat: one", Timestamp: time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "abcd2222", SHA: "f00d0002", Summary: "fix: two", Timestamp: time.Date(2024, 12, 2, 10, 0, 0, 0, time.UTC)},
+		{ID: "9999aaaa", SHA: "beef0003", Summary: "docs: three", Timestamp: time.Date(2024, 12, 3, 10, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name        string
+		refs        []string
+		wantDeleted []string
+		wantErr     string
+	}{
+		{
+			name:        "full ID",
+			refs:        []string{"abcd2222"},
+			wantDeleted: []string{"f00d0002"},
+		},
+		{
+			name:        "SHA prefix",
+			refs:        []string{"beef"},
+			wantDeleted: []string{"beef0003"},
+		},
+		{
+			name:        "upper case ref",
+			refs:        []string{"ABCD1111"},
+			wantDeleted: []string{"f00d0001"},
+		},
+		{
+			name:    "ambiguous prefix",
+			refs:    []string{"abcd"},
+			wantErr: "matches 2 entries",
+		},
+		{
+			name:    "ambiguous ref deletes nothing",
+			refs:    []string{"beef0003", "f00d"},
+			wantErr: "matches 2 entries",
+		},
+		{
+			name:    "unknown ref",
+			refs:    []string{"abcd1111", "0000"},
+			wantErr: "no history entry matches",
+		},
+		{
+			name:    "too short",
+			refs:    []string{"abc"},
+			wantErr: "too short",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			local, central := filepath.Join(dir, "history.jsonl"), filepath.Join(dir, "central.jsonl")
+			for _, path := range []string{local, central} {
+				if err := writeLog(path, entries); err != nil {
+					t.Fatal(err)
+				}
+			}
+			manager := NewManager(local, central, interfaces.Retention{}).(*Manager)
+
+			deleted, err := manager.Delete(tt.refs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Delete() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if got := shas(deleted); strings.Join(got, ",") != strings.Join(tt.wantDeleted, ",") {
+				t.Errorf("Delete() deleted = %v, want %v", got, tt.wantDeleted)
+			}
+
+			// Both stores keep everything that was not deleted
+			for _, path := range []string{local, central} {
+				remaining, _, err := readLog(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(remaining)+len(tt.wantDeleted) != len(entries) {
+					t.Errorf("%s holds %v after Delete(%v)", filepath.Base(path), shas(remaining), tt.refs)
+				}
+			}
+		})
+	}
+}
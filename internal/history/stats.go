@@ -0,0 +1,150 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// dayFormat keys calendar days for streaks
+const dayFormat = "2006-01-02"
+
+// computeStats tallies entries into HistoryStats, with streaks relative to now
+func computeStats(entries []interfaces.HistoryEntry, now time.Time) interfaces.HistoryStats {
+	stats := interfaces.HistoryStats{TotalCommits: len(entries)}
+
+	months := make(map[string]int)
+	monthStarts := make(map[string]time.Time)
+	days := make(map[string]bool)
+	types := make(map[string]int)
+	scopes := make(map[string]int)
+	messageLength := 0
+
+	for _, entry := range entries {
+		messageLength += len([]rune(strings.TrimSpace(entry.Summary)))
+
+		switch entry.Source {
+		case interfaces.SourceGenerated:
+			stats.Generated++
+		case interfaces.SourceEdited:
+			stats.Edited++
+		}
+
+		commitType, scope := ParseConventional(entry.Summary)
+		if commitType != "" {
+			types[commitType]++
+		}
+		if scope != "" {
+			scopes[scope]++
+		}
+
+		if entry.Timestamp.IsZero() {
+			continue
+		}
+		month := entry.Timestamp.Format("Jan 2006")
+		months[month]++
+		monthStarts[month] = time.Date(entry.Timestamp.Year(), entry.Timestamp.Month(), 1, 0, 0, 0, 0, time.UTC)
+		days[entry.Timestamp.Format(dayFormat)] = true
+		stats.Weekdays[entry.Timestamp.Weekday()]++
+		if hasTimeOfDay(entry) {
+			stats.Hours[entry.Timestamp.Hour()]++
+		}
+	}
+
+	if len(entries) > 0 {
+		stats.AverageMessage = float64(messageLength) / float64(len(entries))
+	}
+
+	// Months oldest first; the busiest month wins, ties going to the most recent
+	for month, count := range months {
+		stats.Monthly = append(stats.Monthly, interfaces.Count{Name: month, Count: count})
+	}
+	sort.Slice(stats.Monthly, func(i, j int) bool {
+		return monthStarts[stats.Monthly[i].Name].Before(monthStarts[stats.Monthly[j].Name])
+	})
+	best := 0
+	for _, month := range stats.Monthly {
+		if month.Count >= best {
+			best, stats.MostActiveMonth = month.Count, month.Name
+		}
+	}
+
+	stats.Types = rankCounts(types)
+	stats.Scopes = rankCounts(scopes)
+	stats.CurrentStreak, stats.LongestStreak = streaks(days, now)
+	return stats
+}
+
+// hasTimeOfDay reports whether an entry's timestamp includes the time of the
+// commit; entries recorded before timestamps were stored only know the day
+func hasTimeOfDay(entry interfaces.HistoryEntry) bool {
+	if entry.SHA != "" || entry.Source != "" {
+		return true
+	}
+	hour, minute, second := entry.Timestamp.Clock()
+	return hour != 0 || minute != 0 || second != 0 || entry.Timestamp.Nanosecond() != 0
+}
+
+// rankCounts orders tallies by count, most first, then by name
+func rankCounts(tallies map[string]int) []interfaces.Count {
+	counts := make([]interfaces.Count, 0, len(tallies))
+	for name, count := range tallies {
+		counts = append(counts, interfaces.Count{Name: name, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// streaks returns the current run of consecutive commit days (ending today,
+// or yesterday when nothing has been committed yet today) and the longest run
+func streaks(days map[string]bool, now time.Time) (current, longest int) {
+	sorted := make([]time.Time, 0, len(days))
+	for day := range days {
+		parsed, err := time.Parse(dayFormat, day)
+		if err == nil {
+			sorted = append(sorted, parsed)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	run := 0
+	for i, day := range sorted {
+		if i > 0 && day.Sub(sorted[i-1]) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	day := now
+	if !days[day.Format(dayFormat)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for days[day.Format(dayFormat)] {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+// Bar renders value as a horizontal bar scaled so that max fills width
+func Bar(value, max, width int) string {
+	if max <= 0 || value <= 0 {
+		return ""
+	}
+	length := value * width / max
+	if length == 0 {
+		length = 1
+	}
+	return strings.Repeat("█", length)
+}
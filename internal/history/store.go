@@ -0,0 +1,273 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// The history is an append-only JSON Lines log: one entry per line, written
+// with a single append under an exclusive lock so that concurrent commits
+// from several terminals or hooks never lose entries. Whole-file rewrites
+// (compaction) go through a temporary file and an atomic rename, so readers
+// always see either the old or the new log.
+
+// readLog reads a history log. A trailing line left incomplete by an
+// interrupted write is dropped and reported through repair, so the next
+// write can compact it away. Before the first write, a history file in the
+// older single JSON document format (the same path without the trailing
+// "l") is read instead.
+func readLog(path string) (entries []interfaces.HistoryEntry, repair bool, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		entries, err := readLegacy(legacyPath(path))
+		return entries, false, err
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading work history file: %v", err)
+	}
+
+	entries = make([]interfaces.HistoryEntry, 0)
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry interfaces.HistoryEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			if i == len(lines)-1 {
+				// No newline after it: the write was cut short
+				return entries, true, nil
+			}
+			return nil, false, fmt.Errorf("error parsing work history line %d: %v", i+1, err)
+		}
+		upgradeEntry(&entry)
+		entries = append(entries, entry)
+	}
+
+	// A complete entry missing only its newline still needs rewriting before appending
+	repair = len(data) > 0 && data[len(data)-1] != '\n'
+	return entries, repair, nil
+}
+
+// readLegacy reads a history file in the single JSON document format
+func readLegacy(path string) ([]interfaces.HistoryEntry, error) {
+	history := &WorkHistory{
+		Entries: make([]interfaces.HistoryEntry, 0),
+	}
+	if path == "" {
+		return history.Entries, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return history.Entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading work history file: %v", err)
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("error parsing work history: %v", err)
+	}
+	for i := range history.Entries {
+		upgradeEntry(&history.Entries[i])
+	}
+	return history.Entries, nil
+}
+
+// legacyPath returns where an older version kept the history now logged at
+// path, or "" when path is not a JSON Lines log
+func legacyPath(path string) string {
+	if !strings.HasSuffix(path, ".jsonl") {
+		return ""
+	}
+	return strings.TrimSuffix(path, "l")
+}
+
+// appendLog adds entries to the log at path, skipping commits it already
+// holds (matched by SHA), and applies the retention limits. It returns every
+// entry in the log afterwards and the entries that were added.
+func appendLog(path string, entries []interfaces.HistoryEntry, retention interfaces.Retention) (stored, added []interfaces.HistoryEntry, err error) {
+	unlock, err := lockLog(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	// Re-read under the lock: another process may have written since Load
+	stored, repair, err := readLog(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	known := make(map[string]bool, len(stored))
+	for _, entry := range stored {
+		if entry.SHA != "" {
+			known[entry.SHA] = true
+		}
+	}
+	for _, entry := range entries {
+		if entry.SHA != "" && known[entry.SHA] {
+			continue
+		}
+		if entry.SHA != "" {
+			known[entry.SHA] = true
+		}
+		added = append(added, entry)
+	}
+
+	// Start a fresh log from the legacy file, drop a torn trailing line or
+	// drop entries past the retention limits
+	kept, removed, trimmed := applyRetention(append(stored, added...), retention, time.Now())
+	if _, statErr := os.Stat(path); repair || os.IsNotExist(statErr) || removed > 0 || trimmed > 0 {
+		if len(kept) == 0 && os.IsNotExist(statErr) {
+			return kept, added, nil
+		}
+		return kept, added, writeLogLocked(path, kept)
+	}
+	if len(added) == 0 {
+		return stored, added, nil
+	}
+
+	data, err := encodeLog(added)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening work history file: %v", err)
+	}
+	defer file.Close()
+
+	// One write, so an interruption leaves at most a torn last line
+	if _, err := file.Write(data); err != nil {
+		return nil, nil, fmt.Errorf("error writing work history file: %v", err)
+	}
+	if err := file.Sync(); err != nil {
+		return nil, nil, fmt.Errorf("error writing work history file: %v", err)
+	}
+
+	return kept, added, nil
+}
+
+// updateLog rewrites the log at path with the entries returned by update,
+// holding the lock throughout so no concurrent append is lost. Nothing is
+// written when update fails or changes nothing.
+func updateLog(path string, update func([]interfaces.HistoryEntry) ([]interfaces.HistoryEntry, bool, error)) error {
+	if _, err := os.Stat(filepath.Dir(path)); os.IsNotExist(err) {
+		return nil // no history has been written there
+	}
+
+	unlock, err := lockLog(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, repair, err := readLog(path)
+	if err != nil {
+		return err
+	}
+
+	updated, changed, err := update(entries)
+	if err != nil || !(changed || repair) {
+		return err
+	}
+	return writeLogLocked(path, updated)
+}
+
+// writeLog replaces the log at path with entries
+func writeLog(path string, entries []interfaces.HistoryEntry) error {
+	unlock, err := lockLog(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return writeLogLocked(path, entries)
+}
+
+// writeLogLocked compacts entries into a temporary file beside path and
+// renames it over the log. The caller holds the lock.
+func writeLogLocked(path string, entries []interfaces.HistoryEntry) error {
+	data, err := encodeLog(entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing work history file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing work history file: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing work history file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing work history file: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("error writing work history file: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error replacing work history file: %v", err)
+	}
+	return nil
+}
+
+// encodeLog renders entries as JSON Lines
+func encodeLog(entries []interfaces.HistoryEntry) ([]byte, error) {
+	var data bytes.Buffer
+	writer := bufio.NewWriter(&data)
+
+	// Keep "Name <email>" authors readable rather than \\u003c-escaped
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return nil, fmt.Errorf("error marshaling work history: %v", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return nil, fmt.Errorf("error marshaling work history: %v", err)
+	}
+	return data.Bytes(), nil
+}
+
+// lockLog takes an exclusive lock guarding the log at path, waiting for
+// other processes to finish writing. The lock lives in a separate
+// "<path>.lock" file because compaction replaces the log itself. The
+// directory must exist: a repository's git directory is never created here.
+func lockLog(path string) (unlock func(), err error) {
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening work history lock: %v", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error locking work history: %v", err)
+	}
+
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,143 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+const (
+	logLineOne = `{"date":"01 Dec 2024","summary":"feat: one","timestamp":"2024-12-01T10:00:00Z","sha":"aaa"}`
+	logLineTwo = `{"date":"02 Dec 2024","summary":"fix: two","timestamp":"2024-12-02T10:00:00Z","sha":"bbb"}`
+)
+
+func TestReadLog(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantSHAs   []string
+		wantRepair bool
+		wantErr    bool
+	}{
+		{
+			name:     "complete log",
+			content:  logLineOne + "\n" + logLineTwo + "\n",
+			wantSHAs: []string{"aaa", "bbb"},
+		},
+		{
+			name:       "torn last line",
+			content:    logLineOne + "\n" + logLineTwo[:40],
+			wantSHAs:   []string{"aaa"},
+			wantRepair: true,
+		},
+		{
+			name:       "last entry missing its newline",
+			content:    logLineOne + "\n" + logLineTwo,
+			wantSHAs:   []string{"aaa", "bbb"},
+			wantRepair: true,
+		},
+		{
+			name:     "blank lines",
+			content:  "\n" + logLineOne + "\n\n",
+			wantSHAs: []string{"aaa"},
+		},
+		{
+			name:    "corrupt line before the end",
+			content: logLineTwo[:40] + "\n" + logLineOne + "\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.jsonl")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, repair, err := readLog(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readLog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if repair != tt.wantRepair {
+				t.Errorf("readLog() repair = %v, want %v", repair, tt.wantRepair)
+			}
+			if got := shas(entries); !reflect.DeepEqual(got, tt.wantSHAs) {
+				t.Errorf("readLog() SHAs = %v, want %v", got, tt.wantSHAs)
+			}
+			for _, entry := range entries {
+				if entry.ID == "" {
+					t.Errorf("readLog() entry %s has no ID", entry.SHA)
+				}
+			}
+		})
+	}
+}
+
+func TestReadLogMissingFile(t *testing.T) {
+	entries, repair, err := readLog(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil || repair || len(entries) != 0 {
+		t.Errorf("readLog() = %v, %v, %v; want no entries", entries, repair, err)
+	}
+}
+
+func TestAppendLogRepairsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(path, []byte(logLineOne+"\n"+logLineTwo[:40]), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := interfaces.HistoryEntry{Summary: "docs: three", Timestamp: time.Date(2024, 12, 3, 10, 0, 0, 0, time.UTC), SHA: "ccc"}
+	if _, _, err := appendLog(path, []interfaces.HistoryEntry{entry}, interfaces.Retention{}); err != nil {
+		t.Fatalf("appendLog() error = %v", err)
+	}
+
+	entries, repair, err := readLog(path)
+	if err != nil {
+		t.Fatalf("readLog() error = %v", err)
+	}
+	if repair {
+		t.Error("readLog() reports a torn line after appendLog rewrote the log")
+	}
+	if got, want := shas(entries), []string{"aaa", "ccc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SHAs after append = %v, want %v", got, want)
+	}
+}
+
+func TestAppendLogSkipsKnownCommits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(path, []byte(logLineOne+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []interfaces.HistoryEntry{
+		{Summary: "feat: one", Timestamp: time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC), SHA: "aaa"},
+		{Summary: "fix: two", Timestamp: time.Date(2024, 12, 2, 10, 0, 0, 0, time.UTC), SHA: "bbb"},
+		{Summary: "fix: two", Timestamp: time.Date(2024, 12, 2, 10, 0, 0, 0, time.UTC), SHA: "bbb"},
+	}
+	stored, added, err := appendLog(path, entries, interfaces.Retention{})
+	if err != nil {
+		t.Fatalf("appendLog() error = %v", err)
+	}
+	if got, want := shas(added), []string{"bbb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("appendLog() added = %v, want %v", got, want)
+	}
+	if got, want := shas(stored), []string{"aaa", "bbb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("appendLog() stored = %v, want %v", got, want)
+	}
+}
+
+func shas(entries []interfaces.HistoryEntry) []string {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.SHA)
+	}
+	return result
+}
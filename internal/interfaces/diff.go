@@ -0,0 +1,241 @@
+package interfaces
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileStatus describes how a file changed within a diff
+type FileStatus string
+
+const (
+	FileAdded    FileStatus = "added"
+	FileDeleted  FileStatus = "deleted"
+	FileModified FileStatus = "modified"
+	FileRenamed  FileStatus = "renamed"
+	FileCopied   FileStatus = "copied"
+)
+
+// LineKind identifies the role of a line inside a hunk
+type LineKind byte
+
+const (
+	LineContext   LineKind = ' '
+	LineAdded     LineKind = '+'
+	LineRemoved   LineKind = '-'
+	LineNoNewline LineKind = '\\'
+)
+
+// Diff is a parsed set of file changes, e.g. the staged changes or a commit
+type Diff struct {
+	Files []FileDiff `json:"files"`
+}
+
+// FileDiff is the patch for a single file within a diff
+type FileDiff struct {
+	Path         string     `json:"path"`
+	OldPath      string     `json:"old_path,omitempty"`
+	Status       FileStatus `json:"status"`
+	Similarity   int        `json:"similarity,omitempty"` // rename/copy similarity percentage
+	OldMode      string     `json:"old_mode,omitempty"`
+	NewMode      string     `json:"new_mode,omitempty"`
+	Binary       bool       `json:"binary"`
+	Generated    bool       `json:"generated"` // linguist-generated or a "Code generated ... DO NOT EDIT" header
+	Additions    int        `json:"additions"`
+	Deletions    int        `json:"deletions"`
+	OldBlob      string     `json:"old_blob,omitempty"`
+	NewBlob      string     `json:"new_blob,omitempty"`
+	OldSize      int64      `json:"old_size,omitempty"` // blob sizes in bytes
+	NewSize      int64      `json:"new_size,omitempty"`
+	AddedBytes   int        `json:"added_bytes"`
+	RemovedBytes int        `json:"removed_bytes"`
+	Header       []string   `json:"header"` // raw "diff --git", "index", "---"/"+++" and binary lines
+	Hunks        []Hunk     `json:"hunks"`
+}
+
+// Hunk is a single "@@ -a,b +c,d @@" section of a file diff
+type Hunk struct {
+	Header   string `json:"header"`
+	OldStart int    `json:"old_start"`
+	OldLines int    `json:"old_lines"`
+	NewStart int    `json:"new_start"`
+	NewLines int    `json:"new_lines"`
+	Section  string `json:"section,omitempty"` // function context git prints after the range
+	Lines    []Line `json:"lines"`
+}
+
+// Line is one line of a hunk with its position in the old and new file
+// (0 when the line does not exist on that side)
+type Line struct {
+	Kind      LineKind `json:"kind"`
+	Content   string   `json:"content"`
+	OldNumber int      `json:"old_number,omitempty"`
+	NewNumber int      `json:"new_number,omitempty"`
+}
+
+// String renders the line as it appears in a unified diff
+func (l Line) String() string {
+	return string(l.Kind) + l.Content
+}
+
+// IsEmpty reports whether the diff contains no file changes
+func (d *Diff) IsEmpty() bool {
+	return d == nil || len(d.Files) == 0
+}
+
+// Paths returns the (new) path of every changed file
+func (d *Diff) Paths() []string {
+	if d == nil {
+		return []string{}
+	}
+	paths := make([]string, len(d.Files))
+	for i, file := range d.Files {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+// Stats returns the total number of added and deleted lines
+func (d *Diff) Stats() (additions, deletions int) {
+	if d == nil {
+		return 0, 0
+	}
+	for _, file := range d.Files {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+	return additions, deletions
+}
+
+// Filter returns a diff with only the files accepted by keep
+func (d *Diff) Filter(keep func(file FileDiff) bool) *Diff {
+	filtered := &Diff{Files: make([]FileDiff, 0)}
+	if d == nil {
+		return filtered
+	}
+	for _, file := range d.Files {
+		if keep(file) {
+			filtered.Files = append(filtered.Files, file)
+		}
+	}
+	return filtered
+}
+
+// Only returns a diff restricted to the given paths
+func (d *Diff) Only(paths []string) *Diff {
+	keep := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		keep[path] = true
+	}
+	return d.Filter(func(file FileDiff) bool {
+		return keep[file.Path]
+	})
+}
+
+// String renders the diff back into unified diff text
+func (d *Diff) String() string {
+	if d == nil {
+		return ""
+	}
+	var builder strings.Builder
+	for _, file := range d.Files {
+		builder.WriteString(file.String())
+	}
+	return builder.String()
+}
+
+// String renders the file diff back into unified diff text
+func (f FileDiff) String() string {
+	var builder strings.Builder
+	for _, line := range f.Header {
+		builder.WriteString(line + "\n")
+	}
+	for _, hunk := range f.Hunks {
+		builder.WriteString(hunk.String())
+	}
+	return builder.String()
+}
+
+// Summary describes the file change in one line, e.g. "api.go (renamed from old.go, +3 -1)"
+func (f FileDiff) Summary() string {
+	var details []string
+	switch f.Status {
+	case FileRenamed, FileCopied:
+		details = append(details, fmt.Sprintf("%s from %s", f.Status, f.OldPath))
+	case FileAdded, FileDeleted:
+		details = append(details, string(f.Status))
+	}
+	if f.OldMode != "" && f.NewMode != "" && f.OldMode != f.NewMode {
+		details = append(details, fmt.Sprintf("mode %s → %s", f.OldMode, f.NewMode))
+	}
+	if f.Generated {
+		details = append(details, "generated")
+	}
+	if f.Binary {
+		details = append(details, "binary")
+	} else {
+		details = append(details, fmt.Sprintf("+%d -%d", f.Additions, f.Deletions))
+	}
+	return fmt.Sprintf("%s (%s)", f.Path, strings.Join(details, ", "))
+}
+
+// SkipReason explains why a file's content should be left out of prompts and
+// reviews ("binary", "generated" or "too large"), or returns "" to include it.
+// A maxBytes of zero or less disables the size check.
+func (f FileDiff) SkipReason(maxBytes int) string {
+	switch {
+	case f.Binary:
+		return "binary"
+	case f.Generated:
+		return "generated"
+	case maxBytes > 0 && f.AddedBytes+f.RemovedBytes > maxBytes:
+		return "too large"
+	}
+	return ""
+}
+
+// PlaceholderLine summarises a skipped file in one line, e.g.
+// "assets/logo.png: binary file added (+12.4 KB / -0 B), content omitted"
+func (f FileDiff) PlaceholderLine(reason string) string {
+	return fmt.Sprintf("%s: %s file %s (+%s / -%s), content omitted",
+		f.Path, reason, f.Status, formatBytes(f.AddedBytes), formatBytes(f.RemovedBytes))
+}
+
+// PromptText renders the diff for an AI prompt, replacing the content of
+// binary, generated and oversized files with a one-line placeholder
+func (d *Diff) PromptText(maxBytes int) string {
+	if d == nil {
+		return ""
+	}
+	var builder strings.Builder
+	for _, file := range d.Files {
+		if reason := file.SkipReason(maxBytes); reason != "" {
+			builder.WriteString(file.PlaceholderLine(reason) + "\n")
+			continue
+		}
+		builder.WriteString(file.String())
+	}
+	return builder.String()
+}
+
+// formatBytes renders a byte count in a human-readable unit
+func formatBytes(size int) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+// String renders the hunk back into unified diff text
+func (h Hunk) String() string {
+	var builder strings.Builder
+	builder.WriteString(h.Header + "\n")
+	for _, line := range h.Lines {
+		builder.WriteString(line.String() + "\n")
+	}
+	return builder.String()
+}
@@ -1,24 +1,71 @@
 package interfaces
 
 import (
+	"strings"
 	"time"
 )
 
 // GitRepository defines the contract for Git operations
 type GitRepository interface {
-	GetDiff() (string, error)
+	RepositoryInfo
+	DiffReader
+	CommitReader
+	CommitWriter
+}
+
+// RepositoryInfo describes the repository, its branches and its users
+type RepositoryInfo interface {
 	GetChangedFiles() ([]string, error)
 	GetCurrentBranch() (string, error)
+	GetDefaultBranch() (string, error)
 	GetRecentCommits() ([]string, error)
 	HasStagedChanges() (bool, error)
-	CommitWithMessage(message string) error
+	GetStatus() (string, error)
+	GetRepoRoot() (string, error)
+	GetGitPath(name string) (string, error)
+	GetSignoff() (Trailer, error)
+	GetRecentAuthors(limit int) ([]string, error)
+}
+
+// DiffReader produces diffs of the index, the working tree and commits
+type DiffReader interface {
+	GetStagedDiff() (*Diff, error)
+	GetAmendDiff() (*Diff, error)
+	GetCommitDiff(rev string) (*Diff, error)
+	GetRangeDiff(from, to string) (*Diff, error)
+	GetStagedPatch() (string, error)
+	GetUnstagedDiff() (string, error)
+}
+
+// CommitReader resolves revisions and reads existing commits
+type CommitReader interface {
+	ResolveRevision(rev string) (string, error)
+	ListCommits(revRange string) ([]string, error)
+	GetMergeBase(a, b string) (string, error)
+	GetCommitMessage(rev string) (string, error)
+	GetCommitInfo(rev string) (*CommitInfo, error)
+	GetLog(since, author string) ([]CommitInfo, error)
+}
+
+// CommitWriter stages changes and creates or rewrites commits
+type CommitWriter interface {
+	StageFiles(paths []string) error
+	StagePatch(patch string) error
+	CommitWithMessage(message string, opts CommitOptions) error
+	CommitSeries(commits []PatchCommit, opts CommitOptions) error
 	EditCommitMessage(message string) (string, error)
-	AnalyzeDiffContext(diff string, ignorePatterns []string) (string, []string)
+	ApplyTrailers(message string, trailers []Trailer) (string, error)
+	RewordCommits(messages map[string]string, opts CommitOptions) error
+	CreateBackupRef(name string) (string, error)
+	ResetSoft(rev string) error
 }
 
 // AIProvider defines the contract for AI interactions
 type AIProvider interface {
-	GenerateCommitMessage(diff string, files []string, branchName, additionalContext string) (string, error)
+	GenerateCommitMessage(req CommitRequest) (string, error)
+	GenerateSquashMessage(messages []string, diff *Diff, branchName string) (string, error)
+	ProposeCommitGroups(chunks []string, branchName string) ([]CommitGroup, error)
+	GenerateReport(title, outline, format string) (string, error)
 	AnalyzeCode(code, analysisType string) (string, error)
 	AddInteraction(interactionType, prompt, response, feedback string)
 	GetContextualPrompt(basePrompt string) string
@@ -29,56 +76,324 @@ type ConfigManager interface {
 	Load() error
 	Save() error
 	Initialize() error
+	Suggest() InitOptions
+	InitializeWith(options InitOptions) error
 	ShouldIgnoreFile(filename string) bool
 	GetProject() ProjectConfig
 	GetAI() AIConfig
 	GetReview() ReviewConfig
+	GetCommit() CommitConfig
+	GetHistory() HistoryConfig
+	GroupByConfig(files []string) []ConfigGroup
+	Describe() []ConfigValue
 }
 
 // HistoryManager defines the contract for work history management
 type HistoryManager interface {
 	Load() error
 	Save() error
-	AddEntry(message string) error
+	AddEntry(entry HistoryEntry) error
+	RecordCommit(repo GitRepository, message, source, replaces string) error
+	Import(commits []CommitInfo, repo string) (int, error)
 	Display(monthYear string) error
-	GetStats() map[string]interface{}
+	DisplayAcrossRepos(monthYear string) error
+	DisplayQuery(q HistoryQuery) error
+	Query(q HistoryQuery) ([]HistoryEntry, error)
+	GetStats() HistoryStats
 	FilterByMonthYear(monthYear string) []HistoryEntry
+	Prune(retention Retention, allRepos, dryRun bool) (removed, trimmed int, err error)
+	Delete(refs []string) ([]HistoryEntry, error)
+	Purge(repo string, clearLocal bool) (int, error)
 }
 
 // CodeReviewer defines the contract for code review operations
 type CodeReviewer interface {
-	PerformReview(diff, reviewType string) (*ReviewResult, error)
-	HandleInteractive(diff string) error
+	PerformReview(diff *Diff, reviewType, additionalContext string) (*ReviewResult, error)
+	HandleInteractive(diff *Diff) error
 	DisplayResults(review *ReviewResult)
 	GetSupportedTypes() []string
 }
 
 // Data structures (these will be shared across packages)
 type ProjectConfig struct {
-	Name        string   `yaml:"name"`
-	Language    string   `yaml:"language"`
-	Overview    string   `yaml:"overview"`
-	Scopes      []string `yaml:"scopes"`
-	Standards   string   `yaml:"standards"`
-	IgnoreFiles []string `yaml:"ignore_files"`
+	Name         string      `yaml:"name"`
+	Language     string      `yaml:"language,omitempty"` // deprecated, see PrimaryLanguage
+	Languages    []string    `yaml:"languages"`
+	Overview     string      `yaml:"overview"`
+	Scopes       []string    `yaml:"scopes"`
+	ScopeMap     []ScopeRule `yaml:"scope_map"`
+	DetectScopes bool        `yaml:"detect_scopes"`
+	Standards    string      `yaml:"standards"`
+	IgnoreFiles  []string    `yaml:"ignore_files"`
+}
+
+// ConfigValue is one effective setting, e.g. {"ai.model", "gemini-2.0-flash", "default"}
+type ConfigValue struct {
+	Key    string
+	Value  string
+	Origin string // "default", "environment" or the path of the file that set it
+}
+
+// ConfigGroup is a set of changed files that share the same effective
+// configuration: the root settings merged with every nested .codegenius.yaml
+// between the repository root and the files
+type ConfigGroup struct {
+	Dir     string // directory of the innermost override, "" for the root config
+	Files   []string
+	Project ProjectConfig
+	AI      AIConfig
+	Review  ReviewConfig
+}
+
+// Label names the group for prompts and output, e.g. "frontend/" or "root"
+func (g ConfigGroup) Label() string {
+	if g.Dir == "" {
+		return "root"
+	}
+	return g.Dir + "/"
+}
+
+// Heading introduces the group's files in a prompt with the conventions that apply to them
+func (g ConfigGroup) Heading() string {
+	heading := "# Files under " + g.Label()
+	if language := g.Project.PrimaryLanguage(); language != "" {
+		heading += " (language: " + language + ")"
+	}
+	if g.Project.Standards != "" {
+		heading += "\n# Standards: " + g.Project.Standards
+	}
+	return heading + "\n"
+}
+
+// ShouldIgnoreFile checks a file against the group's ignore patterns
+func (g ConfigGroup) ShouldIgnoreFile(filename string) bool {
+	return MatchesIgnorePattern(g.Project.IgnoreFiles, filename)
+}
+
+// ReviewTypeEnabled reports whether a review type applies to the group; an
+// empty list enables every type
+func (g ConfigGroup) ReviewTypeEnabled(reviewType string) bool {
+	if len(g.Review.EnabledTypes) == 0 {
+		return true
+	}
+	for _, enabled := range g.Review.EnabledTypes {
+		if enabled == reviewType {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesIgnorePattern reports whether a file matches one of the ignore_files
+// patterns, which are substrings ("node_modules/") or suffixes ("*.lock")
+func MatchesIgnorePattern(patterns []string, filename string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(filename, pattern) ||
+			strings.HasSuffix(filename, strings.TrimPrefix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// InitOptions are the project settings chosen when initializing a config file
+type InitOptions struct {
+	Name        string
+	Overview    string
+	Languages   []string // the first is the primary language
+	Scopes      []string
+	IgnoreFiles []string
+	ReviewTypes []string
+}
+
+// PrimaryLanguage returns the project's main language: the first of Languages,
+// or the deprecated single Language setting
+func (p ProjectConfig) PrimaryLanguage() string {
+	if len(p.Languages) > 0 {
+		return p.Languages[0]
+	}
+	return p.Language
+}
+
+// ScopeRule maps paths matching a glob (e.g. "services/billing/**") to a commit scope
+type ScopeRule struct {
+	Pattern string `yaml:"pattern"`
+	Scope   string `yaml:"scope"`
 }
 
 type AIConfig struct {
 	Model            string            `yaml:"model"`
 	ContextTemplates map[string]string `yaml:"context_templates"`
 	MaxTokens        int               `yaml:"max_tokens"`
+	MaxFileDiffBytes int               `yaml:"max_file_diff_bytes"`
 }
 
 type ReviewConfig struct {
-	EnabledTypes     []string          `yaml:"enabled_types"`
-	TextOnly         bool              `yaml:"text_only"`
-	SecurityPatterns []string          `yaml:"security_patterns"`
-	CustomRules      map[string]string `yaml:"custom_rules"`
+	EnabledTypes        []string          `yaml:"enabled_types"`
+	TextOnly            bool              `yaml:"text_only"`
+	SecurityPatterns    []string          `yaml:"security_patterns"`
+	CustomRules         map[string]string `yaml:"custom_rules"`
+	IncludeSkippedFiles bool              `yaml:"include_skipped_files"`
+}
+
+// HistoryConfig controls where work history is kept and for how long
+type HistoryConfig struct {
+	CentralStore      bool `yaml:"central_store"`       // also record every repository's commits in one user-wide store
+	SessionGapMinutes int  `yaml:"session_gap_minutes"` // timesheets: a longer pause between commits starts a new session
+	LeadInMinutes     int  `yaml:"lead_in_minutes"`     // timesheets: work assumed before the first commit of a session
+	MaxAgeDays        int  `yaml:"max_age_days"`        // drop entries older than this, 0 keeps them forever
+	MaxEntries        int  `yaml:"max_entries"`         // keep only the newest entries of a repository, 0 for no limit
+	SubjectOnly       bool `yaml:"subject_only"`        // store commit subject lines without their bodies
 }
 
+// Retention returns the history retention limits set by the configuration
+func (c HistoryConfig) Retention() Retention {
+	return Retention{
+		MaxAge:      time.Duration(c.MaxAgeDays) * 24 * time.Hour,
+		MaxEntries:  c.MaxEntries,
+		SubjectOnly: c.SubjectOnly,
+	}
+}
+
+// Retention limits how much work history is kept. Zero values keep everything.
+type Retention struct {
+	MaxAge      time.Duration
+	MaxEntries  int
+	SubjectOnly bool
+}
+
+type CommitConfig struct {
+	Signoff    bool      `yaml:"signoff"`
+	Sign       bool      `yaml:"sign"`
+	SigningKey string    `yaml:"signing_key"`
+	Team       []string  `yaml:"team"`
+	Trailers   []Trailer `yaml:"trailers"`
+}
+
+// CommitOptions holds git commit flags that are passed through to `git commit`
+type CommitOptions struct {
+	Sign       bool   // -S, sign with GPG or SSH depending on gpg.format
+	SigningKey string // key id passed as -S<key>
+	NoVerify   bool   // --no-verify, skip pre-commit and commit-msg hooks
+	Amend      bool   // --amend, replace the tip of the current branch
+	AllowEmpty bool   // --allow-empty
+	Author     string // --author="Name <email>"
+	Date       string // --date=<date>
+}
+
+// Trailer represents a git commit message trailer such as "Signed-off-by"
+type Trailer struct {
+	Key   string `yaml:"key" json:"key"`
+	Value string `yaml:"value" json:"value"`
+}
+
+// CommitRequest describes the changes a commit message should be generated for
+type CommitRequest struct {
+	Diff    *Diff
+	Branch  string
+	Context string
+	Scope   string // required conventional-commit scope, "" to let the model decide
+}
+
+// CommitGroup is a proposed logical commit made of a subset of staged chunks
+type CommitGroup struct {
+	Message string `json:"message"`
+	Chunks  []int  `json:"chunks"`
+}
+
+// PatchCommit is a commit to create from a patch applied on top of HEAD
+type PatchCommit struct {
+	Message string
+	Patch   string
+}
+
+// History entry sources: how the recorded commit message was produced
+const (
+	SourceGenerated = "generated" // AI message accepted as is
+	SourceEdited    = "edited"    // AI message edited before committing
+	SourceImported  = "imported"  // backfilled from git log
+)
+
+// MessageSource reports whether a committed message is the generated one or
+// was edited first
+func MessageSource(generated, committed string) string {
+	if strings.TrimSpace(generated) == strings.TrimSpace(committed) {
+		return SourceGenerated
+	}
+	return SourceEdited
+}
+
+// HistoryEntry is one commit in the work history. Entries written before
+// commits were recorded in detail only carry Date and Summary.
 type HistoryEntry struct {
-	Date    string `json:"date"`
-	Summary string `json:"summary"`
+	ID           string    `json:"id,omitempty"` // short hash identifying the entry, see history.EntryID
+	Date         string    `json:"date"`         // day, e.g. "02 Jan 2006"
+	Summary      string    `json:"summary"`
+	Timestamp    time.Time `json:"timestamp"` // commit time with its timezone
+	SHA          string    `json:"sha,omitempty"`
+	Branch       string    `json:"branch,omitempty"`
+	Author       string    `json:"author,omitempty"` // "Name <email>"
+	Repo         string    `json:"repo,omitempty"`   // repository root path
+	FilesChanged int       `json:"files_changed"`
+	Insertions   int       `json:"insertions"`
+	Deletions    int       `json:"deletions"`
+	Source       string    `json:"source,omitempty"`
+}
+
+// HistoryQuery filters work history entries. Empty fields match everything.
+type HistoryQuery struct {
+	Since    string // natural or ISO date, e.g. "last week", "yesterday", "2024-12-01"
+	Until    string // inclusive; a day includes all of it
+	Period   string // month, year or ISO week, e.g. "Dec 2024", "2024", "2024-W50"
+	Author   string // substring of "Name <email>"
+	Repo     string // repository directory name or path
+	Branch   string
+	Type     string // conventional commit type, e.g. "feat"
+	Scope    string // conventional commit scope
+	Text     string // free text searched in the commit message
+	AllRepos bool   // search the central store instead of this repository
+}
+
+// Count is a labelled tally, e.g. a month or a commit type and its commits
+type Count struct {
+	Name  string
+	Count int
+}
+
+// HistoryStats summarises the work history
+type HistoryStats struct {
+	TotalCommits    int
+	Monthly         []Count // "Jan 2006" months, oldest first
+	MostActiveMonth string  // ties go to the most recent month
+	CurrentStreak   int     // consecutive days with commits, ending today or yesterday
+	LongestStreak   int
+	Weekdays        [7]int  // commits per day of the week, indexed by time.Weekday
+	Hours           [24]int // commits per hour of the day, in the commit's timezone
+	Types           []Count // conventional commit types, most used first
+	Scopes          []Count // conventional commit scopes, most used first
+	AverageMessage  float64 // average commit message length in characters
+	Generated       int     // AI messages committed as generated
+	Edited          int     // AI messages edited before committing
+}
+
+// AcceptanceRate is the share of AI messages committed without edits, from 0
+// to 1, or -1 when no AI messages were recorded
+func (s HistoryStats) AcceptanceRate() float64 {
+	if s.Generated+s.Edited == 0 {
+		return -1
+	}
+	return float64(s.Generated) / float64(s.Generated+s.Edited)
+}
+
+// CommitInfo describes an existing commit
+type CommitInfo struct {
+	SHA          string
+	Author       string
+	Timestamp    time.Time // committer date: when the commit was made, amended or rebased
+	Message      string
+	FilesChanged int
+	Insertions   int
+	Deletions    int
 }
 
 type ReviewResult struct {
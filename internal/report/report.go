@@ -0,0 +1,185 @@
+// Package report turns work history entries into standup and weekly reports
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/history"
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// Output formats
+const (
+	FormatMarkdown = "markdown"
+	FormatSlack    = "slack" // Slack mrkdwn: *bold* headings and • bullets
+)
+
+// typeSection is a heading the fallback report groups commit types under
+type typeSection struct {
+	title string
+	types []string
+}
+
+// typeSections lists the fallback headings in display order. Commits whose
+// type is not listed, or that are not conventional, go under "Other".
+var typeSections = []typeSection{
+	{title: "✨ Features", types: []string{"feat"}},
+	{title: "🐛 Fixes", types: []string{"fix"}},
+	{title: "⚡ Performance", types: []string{"perf"}},
+	{title: "♻️ Refactoring", types: []string{"refactor", "style"}},
+	{title: "📝 Documentation", types: []string{"docs"}},
+	{title: "✅ Tests", types: []string{"test"}},
+	{title: "🔧 Maintenance", types: []string{"chore", "build", "ci", "revert"}},
+}
+
+// otherSection collects commits without a known type
+const otherSection = "📌 Other"
+
+// ValidFormat reports whether format is a supported output format
+func ValidFormat(format string) bool {
+	return format == FormatMarkdown || format == FormatSlack
+}
+
+// Outline lists entries grouped by repository and then scope, as plain text
+// for the AI to summarise
+func Outline(entries []interfaces.HistoryEntry) string {
+	groups := make(map[string]map[string][]string)
+	for _, entry := range entries {
+		repo := repoLabel(entry.Repo)
+		_, scope := history.ParseConventional(entry.Summary)
+		if scope == "" {
+			scope = "general"
+		}
+		if groups[repo] == nil {
+			groups[repo] = make(map[string][]string)
+		}
+		line := fmt.Sprintf("%s %s", entry.Timestamp.Format("Mon 15:04"), subject(entry.Summary))
+		if entry.FilesChanged > 0 {
+			line += fmt.Sprintf(" (%d files, +%d -%d)", entry.FilesChanged, entry.Insertions, entry.Deletions)
+		}
+		groups[repo][scope] = append(groups[repo][scope], line)
+	}
+
+	repoNames := make([]string, 0, len(groups))
+	for repo := range groups {
+		repoNames = append(repoNames, repo)
+	}
+	sort.Strings(repoNames)
+
+	var outline strings.Builder
+	for _, repo := range repoNames {
+		outline.WriteString(fmt.Sprintf("Repository %s:\n", repo))
+		scopes := make([]string, 0, len(groups[repo]))
+		for scope := range groups[repo] {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+		for _, scope := range scopes {
+			outline.WriteString(fmt.Sprintf("  Scope %s:\n", scope))
+			for _, line := range groups[repo][scope] {
+				outline.WriteString("    - " + line + "\n")
+			}
+		}
+	}
+	return outline.String()
+}
+
+// Fallback renders the report without AI: commits grouped by conventional
+// type, each labelled with its repository (when several are involved) and scope
+func Fallback(title string, entries []interfaces.HistoryEntry, format string) string {
+	sections := make(map[string][]string)
+	multiRepo := len(repos(entries)) > 1
+
+	for _, entry := range entries {
+		commitType, scope := history.ParseConventional(entry.Summary)
+
+		var label []string
+		if multiRepo {
+			label = append(label, repoLabel(entry.Repo))
+		}
+		if scope != "" {
+			label = append(label, scope)
+		}
+
+		// The section already names the type, so drop the "type(scope): " prefix
+		line := subject(entry.Summary)
+		if commitType != "" {
+			line = strings.TrimSpace(line[strings.Index(line, ":")+1:])
+		}
+		if len(label) > 0 {
+			if format == FormatSlack {
+				line = fmt.Sprintf("*%s*: %s", strings.Join(label, "/"), line)
+			} else {
+				line = fmt.Sprintf("**%s**: %s", strings.Join(label, "/"), line)
+			}
+		}
+		section := sectionFor(commitType)
+		sections[section] = append(sections[section], line)
+	}
+
+	var report strings.Builder
+	heading, bullet := "## %s\n", "- "
+	subheading := "\n### %s\n"
+	if format == FormatSlack {
+		heading, bullet, subheading = "*%s*\n", "• ", "\n*%s*\n"
+	}
+
+	report.WriteString(fmt.Sprintf(heading, title))
+	order := make([]string, 0, len(typeSections)+1)
+	for _, section := range typeSections {
+		order = append(order, section.title)
+	}
+	for _, title := range append(order, otherSection) {
+		lines := sections[title]
+		if len(lines) == 0 {
+			continue
+		}
+		report.WriteString(fmt.Sprintf(subheading, title))
+		for _, line := range lines {
+			report.WriteString(bullet + line + "\n")
+		}
+	}
+	report.WriteString(fmt.Sprintf("\n%d commit(s)", len(entries)))
+	if multiRepo {
+		report.WriteString(fmt.Sprintf(" across %d repositories", len(repos(entries))))
+	}
+	report.WriteString("\n")
+	return report.String()
+}
+
+// sectionFor returns the heading a commit type belongs under
+func sectionFor(commitType string) string {
+	for _, section := range typeSections {
+		for _, candidate := range section.types {
+			if candidate == commitType {
+				return section.title
+			}
+		}
+	}
+	return otherSection
+}
+
+// subject returns the first line of a commit message
+func subject(message string) string {
+	return strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+}
+
+// repoLabel names a repository by its directory
+func repoLabel(repo string) string {
+	if repo == "" {
+		return "unknown"
+	}
+	return filepath.Base(repo)
+}
+
+// repos returns the distinct repositories of entries
+func repos(entries []interfaces.HistoryEntry) map[string]bool {
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		seen[repoLabel(entry.Repo)] = true
+	}
+	return seen
+}
@@ -0,0 +1,174 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/history"
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// TimesheetOptions controls how commits are clustered into work sessions
+type TimesheetOptions struct {
+	Gap    time.Duration // a longer pause between commits starts a new session
+	LeadIn time.Duration // work assumed before the first commit of a session
+}
+
+// Session is a run of commits with no pause longer than the gap
+type Session struct {
+	Start    time.Time // first commit minus the lead-in
+	End      time.Time // last commit
+	Commits  int
+	Duration time.Duration
+}
+
+// TimesheetRow is the estimated time spent on one scope of a repository on one day
+type TimesheetRow struct {
+	Date     string // YYYY-MM-DD in the commit's timezone
+	Repo     string
+	Scope    string // conventional commit scope, "" when unscoped
+	Commits  int
+	Duration time.Duration
+}
+
+// Timesheet is the estimate for a set of commits
+type Timesheet struct {
+	Rows     []TimesheetRow
+	Sessions []Session
+	Total    time.Duration
+}
+
+// BuildTimesheet estimates working time from commit timestamps. Sessions are
+// built per repository: within a repository, commits are taken in time order
+// and each is credited with the time since the previous commit in that
+// repository, or with the lead-in when it starts a new session. Work that
+// interleaves several repositories is therefore counted once per repository.
+// The credited time is then totalled per day, repository and scope.
+func BuildTimesheet(entries []interfaces.HistoryEntry, options TimesheetOptions) Timesheet {
+	byRepo := make(map[string][]interfaces.HistoryEntry)
+	for _, entry := range entries {
+		if !entry.Timestamp.IsZero() {
+			byRepo[entry.Repo] = append(byRepo[entry.Repo], entry)
+		}
+	}
+
+	var sheet Timesheet
+	rows := make(map[string]*TimesheetRow)
+	for _, sorted := range byRepo {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+		})
+
+		var session *Session
+		for i, entry := range sorted {
+			credit := options.LeadIn
+			if i > 0 && entry.Timestamp.Sub(sorted[i-1].Timestamp) <= options.Gap {
+				credit = entry.Timestamp.Sub(sorted[i-1].Timestamp)
+			} else {
+				if session != nil {
+					sheet.Sessions = append(sheet.Sessions, *session)
+				}
+				session = &Session{Start: entry.Timestamp.Add(-options.LeadIn)}
+			}
+			session.End = entry.Timestamp
+			session.Commits++
+			session.Duration += credit
+			sheet.Total += credit
+
+			_, scope := history.ParseConventional(entry.Summary)
+			row := TimesheetRow{Date: entry.Timestamp.Format("2006-01-02"), Repo: repoLabel(entry.Repo), Scope: scope}
+			key := row.Date + "\x00" + row.Repo + "\x00" + row.Scope
+			if rows[key] == nil {
+				rows[key] = &row
+			}
+			rows[key].Commits++
+			rows[key].Duration += credit
+		}
+		if session != nil {
+			sheet.Sessions = append(sheet.Sessions, *session)
+		}
+	}
+	sort.SliceStable(sheet.Sessions, func(i, j int) bool {
+		return sheet.Sessions[i].Start.Before(sheet.Sessions[j].Start)
+	})
+
+	for _, row := range rows {
+		sheet.Rows = append(sheet.Rows, *row)
+	}
+	sort.Slice(sheet.Rows, func(i, j int) bool {
+		a, b := sheet.Rows[i], sheet.Rows[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		return a.Scope < b.Scope
+	})
+	return sheet
+}
+
+// WriteCSV writes one row per day, repository and scope with hours as decimals
+func (t Timesheet) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "repo", "scope", "commits", "hours"}); err != nil {
+		return fmt.Errorf("failed to write timesheet: %v", err)
+	}
+	for _, row := range t.Rows {
+		if err := writer.Write([]string{row.Date, row.Repo, row.Scope, strconv.Itoa(row.Commits), formatHours(row.Duration)}); err != nil {
+			return fmt.Errorf("failed to write timesheet: %v", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Markdown renders the timesheet as a table followed by totals per repository and day
+func (t Timesheet) Markdown(title string) string {
+	var sheet strings.Builder
+	sheet.WriteString(fmt.Sprintf("## %s\n\n", title))
+
+	sheet.WriteString("| Date | Repository | Scope | Commits | Hours |\n")
+	sheet.WriteString("|------|------------|-------|--------:|------:|\n")
+	byRepo := make(map[string]time.Duration)
+	byDay := make(map[string]time.Duration)
+	for _, row := range t.Rows {
+		scope := row.Scope
+		if scope == "" {
+			scope = "—"
+		}
+		sheet.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %s |\n", row.Date, row.Repo, scope, row.Commits, formatHours(row.Duration)))
+		byRepo[row.Repo] += row.Duration
+		byDay[row.Date] += row.Duration
+	}
+
+	sheet.WriteString("\n### Hours per repository\n\n")
+	writeTotals(&sheet, byRepo)
+	sheet.WriteString("\n### Hours per day\n\n")
+	writeTotals(&sheet, byDay)
+
+	sheet.WriteString(fmt.Sprintf("\n**Total: %s hours** in %d session(s)\n", formatHours(t.Total), len(t.Sessions)))
+	return sheet.String()
+}
+
+// writeTotals lists durations by key in order
+func writeTotals(sheet *strings.Builder, totals map[string]time.Duration) {
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		sheet.WriteString(fmt.Sprintf("- %s: %s h\n", key, formatHours(totals[key])))
+	}
+}
+
+// formatHours renders a duration as decimal hours, e.g. 1.75
+func formatHours(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Hours(), 'f', 2, 64)
+}
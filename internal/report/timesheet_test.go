@@ -0,0 +1,118 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+func TestBuildTimesheet(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2024, 12, 2, hour, minute, 0, 0, time.UTC) }
+	options := TimesheetOptions{Gap: 2 * time.Hour, LeadIn: 30 * time.Minute}
+
+	tests := []struct {
+		name         string
+		entries      []interfaces.HistoryEntry
+		wantRows     []TimesheetRow
+		wantSessions int
+		wantTotal    time.Duration
+	}{
+		{
+			name: "commits within the gap share a session",
+			entries: []interfaces.HistoryEntry{
+				{Repo: "/src/api", Summary: "fix(api): b", Timestamp: at(10, 0)},
+				{Repo: "/src/api", Summary: "feat(api): a", Timestamp: at(9, 0)},
+				{Repo: "/src/api", Summary: "docs: c", Timestamp: at(11, 0)},
+			},
+			wantRows: []TimesheetRow{
+				{Date: "2024-12-02", Repo: "api", Scope: "", Commits: 1, Duration: time.Hour},
+				{Date: "2024-12-02", Repo: "api", Scope: "api", Commits: 2, Duration: 90 * time.Minute},
+			},
+			wantSessions: 1,
+			wantTotal:    150 * time.Minute,
+		},
+		{
+			name: "a longer pause starts a new session",
+			entries: []interfaces.HistoryEntry{
+				{Repo: "/src/api", Summary: "feat: a", Timestamp: at(9, 0)},
+				{Repo: "/src/api", Summary: "feat: b", Timestamp: at(11, 1)},
+			},
+			wantRows: []TimesheetRow{
+				{Date: "2024-12-02", Repo: "api", Commits: 2, Duration: time.Hour},
+			},
+			wantSessions: 2,
+			wantTotal:    time.Hour,
+		},
+		{
+			name: "sessions are built per repository",
+			entries: []interfaces.HistoryEntry{
+				{Repo: "/src/api", Summary: "feat: a", Timestamp: at(9, 0)},
+				{Repo: "/src/web", Summary: "feat: b", Timestamp: at(9, 45)},
+				{Repo: "/src/api", Summary: "feat: c", Timestamp: at(10, 0)},
+			},
+			wantRows: []TimesheetRow{
+				{Date: "2024-12-02", Repo: "api", Commits: 2, Duration: 90 * time.Minute},
+				{Date: "2024-12-02", Repo: "web", Commits: 1, Duration: 30 * time.Minute},
+			},
+			wantSessions: 2,
+			wantTotal:    2 * time.Hour,
+		},
+		{
+			name: "entries without a timestamp are skipped",
+			entries: []interfaces.HistoryEntry{
+				{Summary: "feat: legacy"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet := BuildTimesheet(tt.entries, options)
+			if !reflect.DeepEqual(sheet.Rows, tt.wantRows) {
+				t.Errorf("BuildTimesheet() rows =\n%+v\nwant\n%+v", sheet.Rows, tt.wantRows)
+			}
+			if len(sheet.Sessions) != tt.wantSessions {
+				t.Errorf("BuildTimesheet() sessions = %d, want %d", len(sheet.Sessions), tt.wantSessions)
+			}
+			if sheet.Total != tt.wantTotal {
+				t.Errorf("BuildTimesheet() total = %v, want %v", sheet.Total, tt.wantTotal)
+			}
+			for i := 1; i < len(sheet.Sessions); i++ {
+				if sheet.Sessions[i].Start.Before(sheet.Sessions[i-1].Start) {
+					t.Errorf("BuildTimesheet() sessions are not in time order: %+v", sheet.Sessions)
+				}
+			}
+		})
+	}
+}
+
+func TestTimesheetWriteCSV(t *testing.T) {
+	sheet := Timesheet{Rows: []TimesheetRow{
+		{Date: "2024-12-02", Repo: "api", Scope: "auth", Commits: 3, Duration: 105 * time.Minute},
+		{Date: "2024-12-03", Repo: "web, old", Commits: 1, Duration: 30 * time.Minute},
+	}}
+
+	var output bytes.Buffer
+	if err := sheet.WriteCSV(&output); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "date,repo,scope,commits,hours\n2024-12-02,api,auth,3,1.75\n2024-12-03,\"web, old\",,1,0.50\n"
+	if output.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", output.String(), want)
+	}
+
+	if err := sheet.WriteCSV(failingWriter{}); err == nil {
+		t.Error("WriteCSV() ignored a write error")
+	}
+}
+
+// failingWriter rejects every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
@@ -23,8 +23,8 @@ func NewReviewer(config interfaces.ConfigManager, ai interfaces.AIProvider) inte
 }
 
 // HandleInteractive performs an interactive code review
-func (r *Reviewer) HandleInteractive(diff string) error {
-	if diff == "" {
+func (r *Reviewer) HandleInteractive(diff *interfaces.Diff) error {
+	if diff.IsEmpty() {
 		fmt.Println("No changes detected for review.")
 		return nil
 	}
@@ -68,7 +68,7 @@ func (r *Reviewer) HandleInteractive(diff string) error {
 		return fmt.Errorf("invalid review type: %s", selectedType)
 	}
 
-	review, err := r.PerformReview(diff, selectedType)
+	review, err := r.PerformReview(diff, selectedType, "")
 	if err != nil {
 		return fmt.Errorf("review failed: %v", err)
 	}
@@ -77,8 +77,8 @@ func (r *Reviewer) HandleInteractive(diff string) error {
 	return nil
 }
 
-// PerformReview performs a specific type of code review
-func (r *Reviewer) PerformReview(diff, reviewType string) (*interfaces.ReviewResult, error) {
+// PerformReview performs a specific type of code review, optionally steered by additional context
+func (r *Reviewer) PerformReview(diff *interfaces.Diff, reviewType, additionalContext string) (*interfaces.ReviewResult, error) {
 	if err := r.validateDependencies(); err != nil {
 		return nil, fmt.Errorf("review setup error: %v", err)
 	}
@@ -87,7 +87,8 @@ func (r *Reviewer) PerformReview(diff, reviewType string) (*interfaces.ReviewRes
 		return nil, fmt.Errorf("invalid review type: %s", reviewType)
 	}
 
-	if strings.TrimSpace(diff) == "" {
+	code := r.reviewableCode(diff, reviewType)
+	if code == "" {
 		return &interfaces.ReviewResult{
 			Type:        reviewType,
 			Issues:      []interfaces.ReviewItem{},
@@ -96,7 +97,11 @@ func (r *Reviewer) PerformReview(diff, reviewType string) (*interfaces.ReviewRes
 		}, nil
 	}
 
-	response, err := r.aiSession.AnalyzeCode(diff, reviewType)
+	if additionalContext != "" {
+		code = fmt.Sprintf("Additional Context: %s\n\n%s", additionalContext, code)
+	}
+
+	response, err := r.aiSession.AnalyzeCode(code, reviewType)
 	if err != nil {
 		return nil, fmt.Errorf("AI analysis failed: %v", err)
 	}
@@ -105,6 +110,40 @@ func (r *Reviewer) PerformReview(diff, reviewType string) (*interfaces.ReviewRes
 	return review, nil
 }
 
+// reviewableCode renders the parts of the diff a review type applies to, using
+// the effective config of each directory group: groups that disable the review
+// type are left out, along with ignored files and, unless the group opts in,
+// binary, generated and oversized files. Returns "" when nothing is left.
+func (r *Reviewer) reviewableCode(diff *interfaces.Diff, reviewType string) string {
+	groups := r.config.GroupByConfig(diff.Paths())
+	annotate := len(groups) > 1 || (len(groups) == 1 && groups[0].Dir != "")
+
+	var code strings.Builder
+	for _, group := range groups {
+		if !group.ReviewTypeEnabled(reviewType) {
+			continue
+		}
+
+		maxBytes := group.AI.MaxFileDiffBytes
+		groupDiff := diff.Only(group.Files).Filter(func(file interfaces.FileDiff) bool {
+			if group.ShouldIgnoreFile(file.Path) {
+				return false
+			}
+			// Binary, generated and oversized files only add noise to a review
+			return group.Review.IncludeSkippedFiles || file.SkipReason(maxBytes) == ""
+		})
+		if groupDiff.IsEmpty() {
+			continue
+		}
+
+		if annotate {
+			code.WriteString("\n" + group.Heading())
+		}
+		code.WriteString(groupDiff.PromptText(maxBytes))
+	}
+	return code.String()
+}
+
 // GetSupportedTypes returns the list of supported review types
 func (r *Reviewer) GetSupportedTypes() []string {
 	if r.config == nil {
@@ -122,13 +161,13 @@ func (r *Reviewer) GetSupportedTypes() []string {
 }
 
 // performAllReviews performs all enabled review types
-func (r *Reviewer) performAllReviews(diff string) error {
+func (r *Reviewer) performAllReviews(diff *interfaces.Diff) error {
 	supportedTypes := r.GetSupportedTypes()
 
 	for _, reviewType := range supportedTypes {
 		fmt.Printf("\n🔍 Performing %s review...\n", reviewType)
 
-		review, err := r.PerformReview(diff, reviewType)
+		review, err := r.PerformReview(diff, reviewType, "")
 		if err != nil {
 			fmt.Printf("❌ %s review failed: %v\n", reviewType, err)
 			continue
@@ -431,7 +470,7 @@ func (r *Reviewer) GetReviewStats(review *interfaces.ReviewResult) map[string]in
 }
 
 // BatchReview performs multiple review types and returns all results
-func (r *Reviewer) BatchReview(diff string, reviewTypes []string) (map[string]*interfaces.ReviewResult, error) {
+func (r *Reviewer) BatchReview(diff *interfaces.Diff, reviewTypes []string) (map[string]*interfaces.ReviewResult, error) {
 	if err := r.validateDependencies(); err != nil {
 		return nil, fmt.Errorf("review setup error: %v", err)
 	}
@@ -443,7 +482,7 @@ func (r *Reviewer) BatchReview(diff string, reviewTypes []string) (map[string]*i
 			continue // Skip invalid types
 		}
 
-		review, err := r.PerformReview(diff, reviewType)
+		review, err := r.PerformReview(diff, reviewType, "")
 		if err != nil {
 			// Log the error but continue with other reviews
 			fmt.Printf("Warning: %s review failed: %v\n", reviewType, err)
@@ -0,0 +1,289 @@
+// Package scope derives conventional-commit scopes from the paths a change
+// touches, using configured path globs and workspaces detected in monorepos.
+package scope
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+// skipDirs are never searched for nested modules
+var skipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "target": true, "dist": true, "build": true,
+}
+
+// cargoMembersRegex captures the members array of a Cargo [workspace] table
+var cargoMembersRegex = regexp.MustCompile(`(?s)\[workspace\][^\[]*?members\s*=\s*\[(.*?)\]`)
+
+// quotedRegex captures double-quoted TOML strings
+var quotedRegex = regexp.MustCompile(`"([^"]+)"`)
+
+// Resolver maps repository-relative paths to commit scopes. Configured rules
+// are checked first, in order; detected workspace rules follow, most deeply
+// nested first.
+type Resolver struct {
+	rules []interfaces.ScopeRule
+}
+
+// NewResolver builds a resolver from the project configuration, detecting
+// workspaces under root when enabled
+func NewResolver(project interfaces.ProjectConfig, root string) *Resolver {
+	rules := append([]interfaces.ScopeRule{}, project.ScopeMap...)
+	if project.DetectScopes && root != "" {
+		rules = append(rules, Detect(root)...)
+	}
+	return &Resolver{rules: rules}
+}
+
+// Rules returns the rules the resolver checks, in order
+func (r *Resolver) Rules() []interfaces.ScopeRule {
+	return r.rules
+}
+
+// ScopeFor returns the scope of a single path, or "" when no rule matches
+func (r *Resolver) ScopeFor(file string) string {
+	file = filepath.ToSlash(file)
+	for _, rule := range r.rules {
+		if Match(rule.Pattern, file) {
+			return rule.Scope
+		}
+	}
+	return ""
+}
+
+// Resolve returns the distinct scopes touched by the given paths, sorted.
+// Paths outside every rule (e.g. a root README) do not contribute a scope.
+func (r *Resolver) Resolve(files []string) []string {
+	seen := make(map[string]bool)
+	scopes := make([]string, 0)
+	for _, file := range files {
+		scope := r.ScopeFor(file)
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Match reports whether a slash-separated path matches a glob. In addition to
+// the path.Match syntax, "**" matches any number of directories, and a
+// pattern ending in "/**" also matches the directory itself.
+func Match(pattern, file string) bool {
+	return matchSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(file, "/"))
+}
+
+// matchSegments matches pattern segments against path segments
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], parts[0]); err != nil || !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// Detect finds Go modules, npm workspaces and Cargo workspace members below
+// root and returns one rule per package directory, most deeply nested first
+func Detect(root string) []interfaces.ScopeRule {
+	dirs := make(map[string]bool)
+	for _, dir := range detectGoModules(root) {
+		dirs[dir] = true
+	}
+	for _, dir := range detectNpmWorkspaces(root) {
+		dirs[dir] = true
+	}
+	for _, dir := range detectCargoWorkspace(root) {
+		dirs[dir] = true
+	}
+
+	rules := make([]interfaces.ScopeRule, 0, len(dirs))
+	for dir := range dirs {
+		rules = append(rules, interfaces.ScopeRule{Pattern: dir + "/**", Scope: path.Base(dir)})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		depthI, depthJ := strings.Count(rules[i].Pattern, "/"), strings.Count(rules[j].Pattern, "/")
+		if depthI != depthJ {
+			return depthI > depthJ
+		}
+		return rules[i].Pattern < rules[j].Pattern
+	})
+	return rules
+}
+
+// detectGoModules returns the directories of go.mod files below the root
+// module. Tracked files are listed with git, which is fast and never looks
+// inside node_modules or vendor; outside a repository the tree is walked.
+func detectGoModules(root string) []string {
+	cmd := exec.Command("git", "ls-files", "-z", "--", "*go.mod")
+	cmd.Dir = root
+	output, err := cmd.Output()
+	if err != nil {
+		return walkGoModules(root)
+	}
+
+	var dirs []string
+	for _, file := range strings.Split(string(output), "\x00") {
+		if path.Base(file) != "go.mod" {
+			continue
+		}
+		if dir := path.Dir(file); dir != "." && !skipPath(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// skipPath reports whether a repository-relative directory lies inside one
+// that is never searched for nested modules
+func skipPath(dir string) bool {
+	for _, part := range strings.Split(dir, "/") {
+		if skipDirs[part] || strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// walkGoModules finds go.mod files by walking the tree below root
+func walkGoModules(root string) []string {
+	var dirs []string
+	filepath.WalkDir(root, func(file string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if file != root && (skipDirs[entry.Name()] || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() != "go.mod" {
+			return nil
+		}
+		if dir := relativeDir(root, filepath.Dir(file)); dir != "" {
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+	return dirs
+}
+
+// detectNpmWorkspaces expands the "workspaces" globs of the root package.json
+func detectNpmWorkspaces(root string) []string {
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil || len(manifest.Workspaces) == 0 {
+		return nil
+	}
+
+	// Workspaces are either a list of globs or {"packages": [...]} (Yarn)
+	var patterns []string
+	if err := json.Unmarshal(manifest.Workspaces, &patterns); err != nil {
+		var yarn struct {
+			Packages []string `json:"packages"`
+		}
+		if err := json.Unmarshal(manifest.Workspaces, &yarn); err != nil {
+			return nil
+		}
+		patterns = yarn.Packages
+	}
+
+	return expandMembers(root, patterns, "package.json")
+}
+
+// detectCargoWorkspace expands the members of a Cargo [workspace] table
+func detectCargoWorkspace(root string) []string {
+	data, err := os.ReadFile(filepath.Join(root, "Cargo.toml"))
+	if err != nil {
+		return nil
+	}
+
+	match := cargoMembersRegex.FindSubmatch(data)
+	if match == nil {
+		return nil
+	}
+
+	var patterns []string
+	for _, member := range quotedRegex.FindAllSubmatch(match[1], -1) {
+		patterns = append(patterns, string(member[1]))
+	}
+	return expandMembers(root, patterns, "Cargo.toml")
+}
+
+// expandMembers resolves workspace globs to directories containing a manifest
+func expandMembers(root string, patterns []string, manifest string) []string {
+	var dirs []string
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			continue // npm exclusion patterns only narrow the set
+		}
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if _, err := os.Stat(filepath.Join(match, manifest)); err != nil {
+				continue
+			}
+			if dir := relativeDir(root, match); dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+// relativeDir returns dir relative to root with forward slashes, or "" for the root itself
+func relativeDir(root, dir string) string {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// ForChanges resolves the scopes touched by files in the repository. The
+// first result is the commit scope when exactly one scope is touched, and ""
+// otherwise; the second lists every scope touched.
+func ForChanges(repo interfaces.RepositoryInfo, project interfaces.ProjectConfig, files []string) (string, []string) {
+	root, err := repo.GetRepoRoot()
+	if err != nil {
+		root = ""
+	}
+
+	scopes := NewResolver(project, root).Resolve(files)
+	if len(scopes) == 1 {
+		return scopes[0], scopes
+	}
+	return "", scopes
+}
@@ -0,0 +1,107 @@
+package scope
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"api/**", "api/server.go", true},
+		{"api/**", "api/v1/handlers/user.go", true},
+		{"api/**", "api", true},
+		{"api/**", "apis/server.go", false},
+		{"api/**", "web/api/server.go", false},
+		{"/api/**/", "api/server.go", true},
+		{"**/*.proto", "user.proto", true},
+		{"**/*.proto", "proto/v1/user.proto", true},
+		{"**/*.proto", "proto/v1/user.go", false},
+		{"services/*/cmd/**", "services/billing/cmd/main.go", true},
+		{"services/*/cmd/**", "services/billing/internal/cmd/main.go", false},
+		{"packages/**/test/*.ts", "packages/ui/button/test/button.ts", true},
+		{"packages/**/test/*.ts", "packages/test/button.ts", true},
+		{"packages/**/test/*.ts", "packages/ui/test/deep/button.ts", false},
+		{"docs/*.md", "docs/guide.md", true},
+		{"docs/*.md", "docs/guides/setup.md", false},
+		{"**", "anything/at/all.txt", true},
+		{"[", "[", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.file, func(t *testing.T) {
+			if got := Match(tt.pattern, tt.file); got != tt.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	resolver := NewResolver(interfaces.ProjectConfig{ScopeMap: []interfaces.ScopeRule{
+		{Pattern: "api/v2/**", Scope: "api-v2"},
+		{Pattern: "api/**", Scope: "api"},
+		{Pattern: "**/*.md", Scope: "docs"},
+	}}, "")
+
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{name: "first matching rule wins", files: []string{"api/v2/user.go"}, want: []string{"api-v2"}},
+		{name: "distinct scopes are sorted", files: []string{"README.md", "api/server.go", "api/v2/user.go", "api/client.go"}, want: []string{"api", "api-v2", "docs"}},
+		{name: "unmatched paths add nothing", files: []string{"main.go"}, want: []string{}},
+		{name: "OS separators", files: []string{filepath.Join("api", "server.go")}, want: []string{"api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.Resolve(tt.files); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve(%v) = %v, want %v", tt.files, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                           "module example.com/root\n",
+		"tools/lint/go.mod":                "module example.com/lint\n",
+		"node_modules/dep/go.mod":          "module example.com/dep\n",
+		"package.json":                     `{"workspaces": ["packages/*", "!packages/skip"]}`,
+		"packages/ui/package.json":         "{}",
+		"packages/empty/README.md":         "",
+		"Cargo.toml":                       "[workspace]\nmembers = [\"crates/core\"]\n",
+		"crates/core/Cargo.toml":           "[package]\n",
+		"crates/unlisted/Cargo.toml":       "[package]\n",
+		"packages/ui/nested/package.json":  "{}",
+		"services/billing/cmd/placeholder": "",
+	}
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []interfaces.ScopeRule{
+		{Pattern: "crates/core/**", Scope: "core"},
+		{Pattern: "packages/ui/**", Scope: "ui"},
+		{Pattern: "tools/lint/**", Scope: "lint"},
+	}
+	if got := Detect(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("Detect() = %+v, want %+v", got, want)
+	}
+}
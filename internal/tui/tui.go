@@ -3,8 +3,12 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/Shubhpreet-Rana/codegenius/internal/git"
+	"github.com/Shubhpreet-Rana/codegenius/internal/history"
 	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
+	"github.com/Shubhpreet-Rana/codegenius/internal/scope"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -73,6 +77,8 @@ func (t *TUI) MainMenu() error {
 				Description("Choose an action to perform").
 				Options(
 					huh.NewOption("Generate commit message", "commit"),
+					huh.NewOption("Stage changes", "stage"),
+					huh.NewOption("Split staged changes into commits", "split"),
 					huh.NewOption("Perform code review", "review"),
 					huh.NewOption("View work history", "history"),
 					huh.NewOption("Show statistics", "stats"),
@@ -95,6 +101,14 @@ func (t *TUI) handleAction(action string) error {
 	switch action {
 	case "commit":
 		return t.handleCommit()
+	case "split":
+		return t.handleSplit()
+	case "stage":
+		staged, err := t.handleStaging()
+		if err != nil || !staged {
+			return err
+		}
+		return t.handleCommit()
 	case "review":
 		return t.handleReview()
 	case "history":
@@ -121,8 +135,15 @@ func (t *TUI) handleCommit() error {
 
 	if !hasStaged {
 		fmt.Println(warningStyle.Render("⚠️  No staged changes detected"))
-		fmt.Println(infoStyle.Render("Please stage your changes first with 'git add'"))
-		return nil
+
+		staged, err := t.handleStaging()
+		if err != nil {
+			return err
+		}
+		if !staged {
+			fmt.Println(infoStyle.Render("Nothing staged, stage your changes with 'git add' or the staging screen"))
+			return nil
+		}
 	}
 
 	// Get additional context from user
@@ -160,26 +181,39 @@ func (t *TUI) handleCommit() error {
 	}
 
 	// Get git information
-	diff, err := t.service.Git.GetDiff()
+	diff, err := t.service.Git.GetStagedDiff()
 	if err != nil {
 		return fmt.Errorf("error getting git diff: %v", err)
 	}
 
+	branchName, err := t.service.Git.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("error getting current branch: %v", err)
+	}
+
 	files, err := t.service.Git.GetChangedFiles()
 	if err != nil {
 		return fmt.Errorf("error getting changed files: %v", err)
 	}
 
-	branchName, err := t.service.Git.GetCurrentBranch()
-	if err != nil {
-		return fmt.Errorf("error getting current branch: %v", err)
+	commitScope, scopes := scope.ForChanges(t.service.Git, t.service.Config.GetProject(), files)
+	if len(scopes) > 1 {
+		fmt.Println(warningStyle.Render(fmt.Sprintf("⚠️  Staged changes span multiple scopes: %s", strings.Join(scopes, ", "))))
+		fmt.Println(infoStyle.Render("💡 Consider splitting them into one commit per scope"))
+	} else if commitScope != "" {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("📦 Scope: %s", commitScope)))
 	}
 
 	// Display loading message
 	fmt.Println(headerStyle.Render("🧠 Generating commit message..."))
 
 	// Generate commit message
-	message, err := t.service.AI.GenerateCommitMessage(diff, files, branchName, additionalContext)
+	message, err := t.service.AI.GenerateCommitMessage(interfaces.CommitRequest{
+		Diff:    diff,
+		Branch:  branchName,
+		Context: additionalContext,
+		Scope:   commitScope,
+	})
 	if err != nil {
 		return fmt.Errorf("error generating commit message: %v", err)
 	}
@@ -212,11 +246,8 @@ func (t *TUI) handleCommit() error {
 
 	switch action {
 	case "commit":
-		if err := t.service.Git.CommitWithMessage(message); err != nil {
-			return fmt.Errorf("error committing: %v", err)
-		}
-		if err := t.service.History.Load(); err == nil {
-			t.service.History.AddEntry(message)
+		if err := t.commitWithTrailers(message, interfaces.SourceGenerated); err != nil {
+			return err
 		}
 		fmt.Println(successStyle.Render("✅ Changes committed successfully!"))
 
@@ -225,11 +256,8 @@ func (t *TUI) handleCommit() error {
 		if err != nil {
 			return fmt.Errorf("error editing commit message: %v", err)
 		}
-		if err := t.service.Git.CommitWithMessage(editedMessage); err != nil {
-			return fmt.Errorf("error committing: %v", err)
-		}
-		if err := t.service.History.Load(); err == nil {
-			t.service.History.AddEntry(editedMessage)
+		if err := t.commitWithTrailers(editedMessage, interfaces.MessageSource(message, editedMessage)); err != nil {
+			return err
 		}
 		fmt.Println(successStyle.Render("✅ Changes committed successfully!"))
 
@@ -243,15 +271,418 @@ func (t *TUI) handleCommit() error {
 	return nil
 }
 
+// handleStaging lets the user stage whole files or individual hunks from the
+// working tree. It reports whether anything was staged.
+func (t *TUI) handleStaging() (bool, error) {
+	status, err := t.service.Git.GetStatus()
+	if err != nil {
+		return false, fmt.Errorf("error getting git status: %v", err)
+	}
+
+	var entries []git.StatusEntry
+	for _, entry := range git.ParseStatus(status) {
+		if entry.HasUnstagedChanges() {
+			entries = append(entries, entry)
+		}
+	}
+
+	if len(entries) == 0 {
+		fmt.Println(infoStyle.Render("No unstaged or untracked changes found"))
+		return false, nil
+	}
+
+	fileOptions := make([]huh.Option[string], len(entries))
+	for i, entry := range entries {
+		fileOptions[i] = huh.NewOption(fmt.Sprintf("%s %s", entry.Code(), entry.Path), entry.Path)
+	}
+
+	var wholeFiles []string
+	var pickHunks bool
+
+	stageForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("📂 Stage Files").
+				Description("Select whole files to stage (x to toggle)").
+				Options(fileOptions...).
+				Value(&wholeFiles),
+			huh.NewConfirm().
+				Title("✂️  Pick individual hunks from the remaining files?").
+				Value(&pickHunks),
+		),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := stageForm.Run(); err != nil {
+		return false, fmt.Errorf("staging form failed: %v", err)
+	}
+
+	if err := t.service.Git.StageFiles(wholeFiles); err != nil {
+		return false, err
+	}
+	staged := len(wholeFiles) > 0
+
+	if pickHunks {
+		hunksStaged, err := t.stageHunks(wholeFiles)
+		if err != nil {
+			return staged, err
+		}
+		staged = staged || hunksStaged
+	}
+
+	if staged {
+		fmt.Println(successStyle.Render("✅ Changes staged"))
+	}
+	return staged, nil
+}
+
+// stageHunks offers every unstaged hunk (outside already staged files) for
+// selection and applies the chosen ones to the index
+func (t *TUI) stageHunks(skipFiles []string) (bool, error) {
+	diff, err := t.service.Git.GetUnstagedDiff()
+	if err != nil {
+		return false, err
+	}
+
+	skip := make(map[string]bool, len(skipFiles))
+	for _, file := range skipFiles {
+		skip[file] = true
+	}
+
+	plan := git.NewSplitPlan(diff)
+	var options []huh.Option[int]
+	for i, chunk := range plan.Chunks {
+		if skip[plan.Files[chunk.File].Path] {
+			continue
+		}
+		options = append(options, huh.NewOption(plan.Label(i), i))
+	}
+
+	if len(options) == 0 {
+		fmt.Println(infoStyle.Render("No hunks left to stage (untracked files can only be staged whole)"))
+		return false, nil
+	}
+
+	var selected []int
+	hunkForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title("✂️  Stage Hunks").
+				Description("Select the hunks to stage").
+				Options(options...).
+				Value(&selected),
+		),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := hunkForm.Run(); err != nil {
+		return false, fmt.Errorf("hunk selection failed: %v", err)
+	}
+
+	if len(selected) == 0 {
+		return false, nil
+	}
+
+	chunks := make([]git.Chunk, len(selected))
+	for i, index := range selected {
+		chunks[i] = plan.Chunks[index]
+	}
+
+	if err := t.service.Git.StagePatch(git.BuildPatch(plan.Files, chunks)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// commitWithTrailers asks for co-authors and commit options, appends trailers,
+// commits the message and records it in the work history under source
+func (t *TUI) commitWithTrailers(message, source string) error {
+	coAuthors, err := t.selectCoAuthors()
+	if err != nil {
+		return err
+	}
+
+	opts, err := t.selectCommitOptions()
+	if err != nil {
+		return err
+	}
+
+	trailers, err := git.CollectTrailers(t.service.Git, t.service.Config.GetCommit(), git.TrailerOptions{
+		CoAuthors: coAuthors,
+	})
+	if err != nil {
+		return fmt.Errorf("error collecting commit trailers: %v", err)
+	}
+
+	fullMessage, err := t.service.Git.ApplyTrailers(message, trailers)
+	if err != nil {
+		return err
+	}
+
+	// An amend replaces HEAD, so its history entry is replaced too
+	replaces := ""
+	if opts.Amend {
+		replaces, _ = t.service.Git.ResolveRevision("HEAD")
+	}
+
+	if err := t.service.Git.CommitWithMessage(fullMessage, opts); err != nil {
+		return fmt.Errorf("error committing: %v", err)
+	}
+	if err := t.service.History.Load(); err == nil {
+		t.service.History.RecordCommit(t.service.Git, message, source, replaces)
+	}
+	return nil
+}
+
+// selectCoAuthors offers the team roster and recent authors as co-authors
+func (t *TUI) selectCoAuthors() ([]string, error) {
+	candidates := git.CoAuthorCandidates(t.service.Git, t.service.Config.GetCommit().Team)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	options := make([]huh.Option[string], len(candidates))
+	for i, candidate := range candidates {
+		options[i] = huh.NewOption(candidate, candidate)
+	}
+
+	var coAuthors []string
+	coAuthorForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("👥 Co-authors").
+				Description("Select anyone who paired on this change (leave empty for none)").
+				Options(options...).
+				Value(&coAuthors),
+		),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := coAuthorForm.Run(); err != nil {
+		return nil, fmt.Errorf("co-author selection failed: %v", err)
+	}
+
+	return coAuthors, nil
+}
+
+// selectCommitOptions lets the user pick the git commit flags to pass through
+func (t *TUI) selectCommitOptions() (interfaces.CommitOptions, error) {
+	commitConfig := t.service.Config.GetCommit()
+
+	selected := []string{}
+	if commitConfig.Sign {
+		selected = append(selected, "sign")
+	}
+	var author, date string
+
+	optionsForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("⚙️  Commit Options").
+				Description("Flags passed through to git commit").
+				Options(
+					huh.NewOption("🔏 Sign commit (-S)", "sign"),
+					huh.NewOption("⏭️  Skip hooks (--no-verify)", "no-verify"),
+					huh.NewOption("🩹 Amend previous commit (--amend)", "amend"),
+					huh.NewOption("📭 Allow empty commit (--allow-empty)", "allow-empty"),
+				).
+				Value(&selected),
+			huh.NewInput().
+				Title("👤 Author override").
+				Description("Leave empty to use your git identity").
+				Placeholder("Jane Doe <jane@example.com>").
+				Value(&author),
+			huh.NewInput().
+				Title("🕒 Date override").
+				Description("Leave empty to use the current time").
+				Placeholder("2024-12-01T10:00:00").
+				Value(&date),
+		),
+	).WithTheme(huh.ThemeCharm())
+
+	if err := optionsForm.Run(); err != nil {
+		return interfaces.CommitOptions{}, fmt.Errorf("commit options form failed: %v", err)
+	}
+
+	opts := interfaces.CommitOptions{
+		Author: strings.TrimSpace(author),
+		Date:   strings.TrimSpace(date),
+	}
+	for _, option := range selected {
+		switch option {
+		case "sign":
+			opts.Sign = true
+		case "no-verify":
+			opts.NoVerify = true
+		case "amend":
+			opts.Amend = true
+		case "allow-empty":
+			opts.AllowEmpty = true
+		}
+	}
+
+	if !opts.Sign {
+		// Signing was deselected explicitly, so don't let the config turn it back on
+		commitConfig.Sign = false
+		commitConfig.SigningKey = ""
+	}
+	return git.ResolveCommitOptions(commitConfig, opts), nil
+}
+
+// handleSplit splits the staged diff into several commits, letting the user
+// adjust the AI-proposed grouping before anything is committed
+func (t *TUI) handleSplit() error {
+	patch, err := t.service.Git.GetStagedPatch()
+	if err != nil {
+		return fmt.Errorf("error getting staged changes: %v", err)
+	}
+
+	plan := git.NewSplitPlan(patch)
+	if len(plan.Chunks) < 2 {
+		fmt.Println(warningStyle.Render("⚠️  Not enough staged changes to split"))
+		fmt.Println(infoStyle.Render("Use 'Generate commit message' for small changes"))
+		return nil
+	}
+
+	branchName, err := t.service.Git.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("error getting current branch: %v", err)
+	}
+
+	fmt.Println(headerStyle.Render(fmt.Sprintf("🧠 Grouping %d chunks into logical commits...", len(plan.Chunks))))
+	groups, err := t.service.AI.ProposeCommitGroups(plan.Descriptions, branchName)
+	if err != nil {
+		return fmt.Errorf("error proposing commit groups: %v", err)
+	}
+	groups = git.AssignRemaining(git.NormalizeGroups(groups, len(plan.Chunks)), len(plan.Chunks))
+
+	for {
+		t.displaySplitPlan(plan, groups)
+
+		var action string
+		actionForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("🚀 What would you like to do?").
+					Options(
+						huh.NewOption("✅ Create these commits", "commit"),
+						huh.NewOption("✏️  Adjust grouping and messages", "adjust"),
+						huh.NewOption("❌ Cancel", "cancel"),
+					).
+					Value(&action),
+			),
+		).WithTheme(huh.ThemeCharm())
+
+		if err := actionForm.Run(); err != nil {
+			return fmt.Errorf("action form failed: %v", err)
+		}
+
+		switch action {
+		case "adjust":
+			groups, err = t.adjustSplitGroups(plan, groups)
+			if err != nil {
+				return err
+			}
+		case "commit":
+			commits, err := plan.Commits(groups)
+			if err != nil {
+				return err
+			}
+			opts := git.ResolveCommitOptions(t.service.Config.GetCommit(), interfaces.CommitOptions{})
+			if err := t.service.Git.CommitSeries(commits, opts); err != nil {
+				return err
+			}
+			fmt.Println(successStyle.Render(fmt.Sprintf("✅ Created %d commits!", len(commits))))
+			if remaining := git.UnassignedChunks(groups, len(plan.Chunks)); len(remaining) > 0 {
+				fmt.Println(infoStyle.Render(fmt.Sprintf("%d unassigned chunk(s) are still staged", len(remaining))))
+			}
+			return nil
+		default:
+			fmt.Println(infoStyle.Render("🚫 Split cancelled"))
+			return nil
+		}
+	}
+}
+
+// displaySplitPlan prints the proposed commits and their chunks
+func (t *TUI) displaySplitPlan(plan *git.SplitPlan, groups []interfaces.CommitGroup) {
+	var content strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			content.WriteString("\n\n")
+		}
+		content.WriteString(successStyle.Render(fmt.Sprintf("📦 %d. %s", i+1, group.Message)))
+		for _, chunk := range group.Chunks {
+			content.WriteString(fmt.Sprintf("\n   • [%d] %s", chunk, plan.Label(chunk)))
+		}
+	}
+
+	if remaining := git.UnassignedChunks(groups, len(plan.Chunks)); len(remaining) > 0 {
+		content.WriteString("\n\n" + warningStyle.Render("Left staged (not committed):"))
+		for _, chunk := range remaining {
+			content.WriteString(fmt.Sprintf("\n   • [%d] %s", chunk, plan.Label(chunk)))
+		}
+	}
+
+	fmt.Println(containerStyle.Render(
+		titleStyle.Render("Proposed Commits") + "\n\n" + content.String(),
+	))
+}
+
+// adjustSplitGroups lets the user edit each group's message and chunks. A
+// chunk picked by several groups stays with the first one; chunks picked by
+// none are left staged.
+func (t *TUI) adjustSplitGroups(plan *git.SplitPlan, groups []interfaces.CommitGroup) ([]interfaces.CommitGroup, error) {
+	// Offer one empty group so chunks can be moved into a new commit
+	groups = append(groups, interfaces.CommitGroup{})
+
+	formGroups := make([]*huh.Group, len(groups))
+	messages := make([]string, len(groups))
+	selections := make([][]int, len(groups))
+
+	for i, group := range groups {
+		messages[i] = group.Message
+		selections[i] = append([]int(nil), group.Chunks...)
+
+		options := make([]huh.Option[int], len(plan.Chunks))
+		for chunk := range plan.Chunks {
+			options[chunk] = huh.NewOption(fmt.Sprintf("[%d] %s", chunk, plan.Label(chunk)), chunk)
+		}
+
+		title := fmt.Sprintf("📦 Commit %d", i+1)
+		if i == len(groups)-1 {
+			title = "➕ New commit (leave empty to skip)"
+		}
+
+		formGroups[i] = huh.NewGroup(
+			huh.NewInput().
+				Title(title).
+				Description("Commit message").
+				Value(&messages[i]),
+			huh.NewMultiSelect[int]().
+				Title("Chunks").
+				Options(options...).
+				Value(&selections[i]),
+		)
+	}
+
+	if err := huh.NewForm(formGroups...).WithTheme(huh.ThemeCharm()).Run(); err != nil {
+		return nil, fmt.Errorf("split adjustment failed: %v", err)
+	}
+
+	adjusted := make([]interfaces.CommitGroup, len(groups))
+	for i := range groups {
+		adjusted[i] = interfaces.CommitGroup{Message: messages[i], Chunks: selections[i]}
+	}
+	return git.NormalizeGroups(adjusted, len(plan.Chunks)), nil
+}
+
 // handleReview handles code review with multi-select options
 func (t *TUI) handleReview() error {
 	// Check for changes
-	diff, err := t.service.Git.GetDiff()
+	diff, err := t.service.Git.GetStagedDiff()
 	if err != nil {
 		return fmt.Errorf("failed to get git diff: %v", err)
 	}
 
-	if diff == "" {
+	if diff.IsEmpty() {
 		fmt.Println(warningStyle.Render("⚠️  No changes detected for review"))
 		return nil
 	}
@@ -326,13 +757,7 @@ func (t *TUI) handleReview() error {
 	for _, reviewType := range selectedTypes {
 		fmt.Printf("\n%s Analyzing %s...\n", getReviewTypeEmoji(reviewType), reviewType)
 
-		// Add additional context to the diff if provided
-		reviewDiff := diff
-		if additionalContext != "" {
-			reviewDiff = fmt.Sprintf("Additional Context: %s\n\n%s", additionalContext, diff)
-		}
-
-		review, err := t.service.Review.PerformReview(reviewDiff, reviewType)
+		review, err := t.service.Review.PerformReview(diff, reviewType, additionalContext)
 		if err != nil {
 			fmt.Printf("%s %s review failed: %v\n", errorStyle.Render("❌"), reviewType, err)
 			continue
@@ -344,18 +769,19 @@ func (t *TUI) handleReview() error {
 	return nil
 }
 
-// handleHistory handles work history display
+// handleHistory handles work history display, optionally filtered by date
+// range, conventional type and scope, text, author and branch
 func (t *TUI) handleHistory() error {
-	var monthYear string
 	var showAll bool
+	var q interfaces.HistoryQuery
 
 	historyForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
 				Title("📊 Work History").
-				Description("Show all history or filter by month/year?").
+				Description("Show all history or filter it?").
 				Affirmative("Show All").
-				Negative("Filter by Month").
+				Negative("Filter").
 				Value(&showAll),
 		),
 	).WithTheme(huh.ThemeCharm())
@@ -365,13 +791,60 @@ func (t *TUI) handleHistory() error {
 	}
 
 	if !showAll {
+		validDate := func(value string) error {
+			if strings.TrimSpace(value) == "" {
+				return nil
+			}
+			_, err := history.ParseDate(value, time.Now(), false)
+			return err
+		}
+
 		filterForm := huh.NewForm(
 			huh.NewGroup(
 				huh.NewInput().
-					Title("📅 Month/Year Filter").
-					Description("Enter month and year (e.g., 'Dec 2024', 'Jan 2025')").
+					Title("📅 Month, Year or Week").
+					Description("e.g. 'Dec 2024', '2024' or '2024-W50' (optional)").
 					Placeholder("Dec 2024").
-					Value(&monthYear),
+					Validate(func(value string) error {
+						if strings.TrimSpace(value) == "" {
+							return nil
+						}
+						_, _, err := history.ParsePeriod(value)
+						return err
+					}).
+					Value(&q.Period),
+				huh.NewInput().
+					Title("⏪ Since").
+					Description("e.g. 'last week', 'yesterday', '2024-12-01' (optional)").
+					Validate(validDate).
+					Value(&q.Since),
+				huh.NewInput().
+					Title("⏩ Until").
+					Description("Inclusive (optional)").
+					Validate(validDate).
+					Value(&q.Until),
+			),
+			huh.NewGroup(
+				huh.NewInput().
+					Title("🏷️  Type").
+					Description("Conventional commit type, e.g. feat or fix (optional)").
+					Value(&q.Type),
+				huh.NewInput().
+					Title("🎯 Scope").
+					Description("Conventional commit scope (optional)").
+					Value(&q.Scope),
+				huh.NewInput().
+					Title("🔍 Search").
+					Description("Text in the commit message (optional)").
+					Value(&q.Text),
+				huh.NewInput().
+					Title("👤 Author").
+					Description("Name or email (optional)").
+					Value(&q.Author),
+				huh.NewInput().
+					Title("🌿 Branch").
+					Description("(optional)").
+					Value(&q.Branch),
 			),
 		).WithTheme(huh.ThemeCharm())
 
@@ -380,13 +853,39 @@ func (t *TUI) handleHistory() error {
 		}
 	}
 
+	if t.service.Config.GetHistory().CentralStore {
+		reposForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title("📦 Repositories").
+					Description("Show this repository or every repository in the central store?").
+					Affirmative("All Repositories").
+					Negative("This Repository").
+					Value(&q.AllRepos),
+			),
+		).WithTheme(huh.ThemeCharm())
+
+		if err := reposForm.Run(); err != nil {
+			return fmt.Errorf("repository form failed: %v", err)
+		}
+	}
+
 	if err := t.service.History.Load(); err != nil {
 		return fmt.Errorf("failed to load work history: %v", err)
 	}
 
-	return t.service.History.Display(monthYear)
+	if showAll {
+		if q.AllRepos {
+			return t.service.History.DisplayAcrossRepos("")
+		}
+		return t.service.History.Display("")
+	}
+	return t.service.History.DisplayQuery(q)
 }
 
+// chartWidth is the length of the longest bar in the stats charts
+const chartWidth = 30
+
 // handleStats displays statistics
 func (t *TUI) handleStats() error {
 	if err := t.service.History.Load(); err != nil {
@@ -398,48 +897,158 @@ func (t *TUI) handleStats() error {
 	fmt.Println(headerStyle.Render("📊 CodeGenius Statistics"))
 	fmt.Println(strings.Repeat("═", 50))
 
-	if totalCommits, ok := stats["total_commits"].(int); ok {
-		fmt.Printf("📈 Total commits: %s\n", successStyle.Render(fmt.Sprintf("%d", totalCommits)))
+	fmt.Printf("📈 Total commits: %s\n", successStyle.Render(fmt.Sprintf("%d", stats.TotalCommits)))
+	if stats.TotalCommits == 0 {
+		fmt.Println(infoStyle.Render("💡 Commit with CodeGenius or run 'codegenius history import' to see statistics."))
+		fmt.Println()
+		return nil
 	}
 
-	if mostActive, ok := stats["most_active_month"].(string); ok && mostActive != "" {
-		fmt.Printf("🏆 Most active month: %s\n", successStyle.Render(mostActive))
+	if stats.MostActiveMonth != "" {
+		fmt.Printf("🏆 Most active month: %s\n", successStyle.Render(stats.MostActiveMonth))
+	}
+	fmt.Printf("🔥 Current streak: %s (longest %d)\n", successStyle.Render(fmt.Sprintf("%d day(s)", stats.CurrentStreak)), stats.LongestStreak)
+	fmt.Printf("✏️  Average message: %s\n", successStyle.Render(fmt.Sprintf("%.0f characters", stats.AverageMessage)))
+	if rate := stats.AcceptanceRate(); rate >= 0 {
+		fmt.Printf("🤖 AI messages accepted unedited: %s %s\n",
+			successStyle.Render(fmt.Sprintf("%.0f%%", rate*100)),
+			infoStyle.Render(fmt.Sprintf("(%d generated, %d edited)", stats.Generated, stats.Edited)))
 	}
 
-	if breakdown, ok := stats["monthly_breakdown"].(map[string]int); ok && len(breakdown) > 0 {
-		fmt.Println(headerStyle.Render("\n📅 Monthly Breakdown:"))
-		for month, count := range breakdown {
-			fmt.Printf("  %s: %s commits\n", month, successStyle.Render(fmt.Sprintf("%d", count)))
+	// Monday first, the way a working week reads
+	weekdays := make([]interfaces.Count, 0, 7)
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		weekdays = append(weekdays, interfaces.Count{Name: day.String()[:3], Count: stats.Weekdays[day]})
+	}
+	printChart("📆 By day of week", weekdays)
+
+	// Only the span of hours that saw commits, so a 9-to-5 doesn't print 24 rows
+	first, last := -1, -1
+	for hour, count := range stats.Hours {
+		if count > 0 {
+			if first < 0 {
+				first = hour
+			}
+			last = hour
 		}
 	}
+	var hours []interfaces.Count
+	for hour := first; first >= 0 && hour <= last; hour++ {
+		hours = append(hours, interfaces.Count{Name: fmt.Sprintf("%02d:00", hour), Count: stats.Hours[hour]})
+	}
+	printChart("🕐 By hour of day", hours)
+
+	printChart("🏷️  Commit types", stats.Types)
+	printChart("📦 Scopes", stats.Scopes)
+	printChart("📅 Monthly breakdown", stats.Monthly)
 
 	fmt.Println()
 	return nil
 }
 
-// handleInit handles configuration initialization
+// printChart prints counts as a horizontal bar chart, skipping empty charts
+func printChart(title string, counts []interfaces.Count) {
+	max, labelWidth := 0, 0
+	for _, count := range counts {
+		if count.Count > max {
+			max = count.Count
+		}
+		if len(count.Name) > labelWidth {
+			labelWidth = len(count.Name)
+		}
+	}
+	if max == 0 {
+		return
+	}
+
+	fmt.Println(headerStyle.Render(title))
+	for _, count := range counts {
+		fmt.Printf("  %-*s %s %s\n", labelWidth, count.Name,
+			successStyle.Render(history.Bar(count.Count, max, chartWidth)),
+			infoStyle.Render(fmt.Sprintf("%d", count.Count)))
+	}
+}
+
+// handleInit runs the configuration wizard from the menu
 func (t *TUI) handleInit() error {
-	var confirm bool
+	return t.RunInitWizard()
+}
+
+// RunInitWizard guides the user through creating the project configuration,
+// pre-filling every answer with what was detected in the repository
+func (t *TUI) RunInitWizard() error {
+	fmt.Println(headerStyle.Render("🔍 Inspecting repository..."))
+	suggested := t.service.Config.Suggest()
+
+	options := suggested
+	extraScopes := ""
 
-	initForm := huh.NewForm(
+	languageOptions := selectedOptions(mergeUnique(suggested.Languages, commonLanguages), suggested.Languages)
+	scopeOptions := selectedOptions(suggested.Scopes, suggested.Scopes)
+	ignoreOptions := selectedOptions(suggested.IgnoreFiles, suggested.IgnoreFiles)
+	reviewOptions := selectedOptions(suggested.ReviewTypes, suggested.ReviewTypes)
+
+	wizard := huh.NewForm(
 		huh.NewGroup(
-			huh.NewConfirm().
-				Title("⚙️  Initialize Configuration").
-				Description("This will create a new configuration file. Continue?").
-				Value(&confirm),
+			huh.NewInput().
+				Title("📛 Project name").
+				Description("Detected from the git remote").
+				Value(&options.Name),
+			huh.NewText().
+				Title("📝 Overview").
+				Description("One or two sentences the AI uses as background").
+				CharLimit(500).
+				Value(&options.Overview),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("🧑‍💻 Languages").
+				Description("Detected languages are preselected; the first is the primary one").
+				Options(languageOptions...).
+				Value(&options.Languages),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("📦 Commit scopes").
+				Description("From the directory layout and recent commit subjects").
+				Options(scopeOptions...).
+				Value(&options.Scopes),
+			huh.NewInput().
+				Title("➕ Additional scopes").
+				Description("Comma-separated, optional").
+				Value(&extraScopes),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("🙈 Ignore patterns").
+				Description("Files left out of reviews").
+				Options(ignoreOptions...).
+				Value(&options.IgnoreFiles),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("🔍 Review types").
+				Description("Which reviews to offer").
+				Options(reviewOptions...).
+				Value(&options.ReviewTypes),
 		),
 	).WithTheme(huh.ThemeCharm())
 
-	if err := initForm.Run(); err != nil {
-		return fmt.Errorf("init form failed: %v", err)
+	if err := wizard.Run(); err != nil {
+		return fmt.Errorf("init wizard failed: %v", err)
 	}
 
-	if !confirm {
-		fmt.Println(infoStyle.Render("🚫 Configuration initialization cancelled"))
-		return nil
+	for _, scope := range strings.Split(extraScopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			options.Scopes = mergeUnique(options.Scopes, []string{scope})
+		}
 	}
 
-	if err := t.service.Config.Initialize(); err != nil {
+	// MultiSelect returns choices in option order, so keep the detected primary language first
+	options.Languages = mergeUnique(intersect(suggested.Languages, options.Languages), options.Languages)
+
+	if err := t.service.Config.InitializeWith(options); err != nil {
 		return fmt.Errorf("failed to initialize configuration: %v", err)
 	}
 
@@ -447,6 +1056,50 @@ func (t *TUI) handleInit() error {
 	return nil
 }
 
+// commonLanguages are offered in the wizard in addition to detected ones
+var commonLanguages = []string{"go", "typescript", "javascript", "python", "rust", "java", "kotlin", "ruby", "php", "csharp"}
+
+// selectedOptions builds huh options, preselecting the given values
+func selectedOptions(values, selected []string) []huh.Option[string] {
+	options := make([]huh.Option[string], 0, len(values))
+	for _, value := range values {
+		options = append(options, huh.NewOption(value, value).Selected(containsValue(selected, value)))
+	}
+	return options
+}
+
+// mergeUnique appends the values of extra missing from base, keeping order
+func mergeUnique(base, extra []string) []string {
+	merged := append([]string{}, base...)
+	for _, value := range extra {
+		if !containsValue(merged, value) {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+// intersect returns the values of a that are also in b, in a's order
+func intersect(a, b []string) []string {
+	var common []string
+	for _, value := range a {
+		if containsValue(b, value) {
+			common = append(common, value)
+		}
+	}
+	return common
+}
+
+// containsValue reports whether values contains value
+func containsValue(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
 // displayReviewResults shows the review results with beautiful formatting
 func (t *TUI) displayReviewResults(review *interfaces.ReviewResult) {
 	fmt.Print(containerStyle.Render(
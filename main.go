@@ -4,27 +4,59 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/Shubhpreet-Rana/codegenius/internal/ai"
+	"github.com/Shubhpreet-Rana/codegenius/internal/cli"
 	"github.com/Shubhpreet-Rana/codegenius/internal/config"
 	"github.com/Shubhpreet-Rana/codegenius/internal/container"
 	"github.com/Shubhpreet-Rana/codegenius/internal/git"
 	"github.com/Shubhpreet-Rana/codegenius/internal/history"
 	"github.com/Shubhpreet-Rana/codegenius/internal/interfaces"
 	"github.com/Shubhpreet-Rana/codegenius/internal/review"
+	"github.com/Shubhpreet-Rana/codegenius/internal/scope"
 	"github.com/Shubhpreet-Rana/codegenius/internal/tui"
 )
 
 func main() {
+	// Positional subcommands (e.g. `codegenius reword HEAD`) have their own flags
+	if len(os.Args) > 1 {
+		if command, ok := cli.Commands[os.Args[1]]; ok {
+			runSubcommand(command, os.Args[2:])
+			return
+		}
+		if command, ok := cli.Standalone[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil && err != flag.ErrHelp {
+				log.Fatalf("❌ %v", err)
+			}
+			return
+		}
+	}
+
 	var (
 		reviewFlag      = flag.Bool("review", false, "Perform code review")
 		historyFlag     = flag.String("history", "", "Display work history for month-year (e.g., 'Dec 2024')")
+		allReposFlag    = flag.Bool("all-repos", false, "With --history, show every repository from the central history store")
 		initFlag        = flag.Bool("init", false, "Initialize configuration")
+		nonInteractive  = flag.Bool("non-interactive", false, "With --init, write the detected settings without asking")
 		interactiveFlag = flag.Bool("interactive", false, "Run in interactive mode")
 		tuiFlag         = flag.Bool("tui", false, "Run with beautiful terminal UI (recommended)")
 		helpFlag        = flag.Bool("help", false, "Show help information")
+		signoffFlag     = flag.Bool("signoff", false, "Add a Signed-off-by trailer to the commit message")
+		signFlag        = flag.Bool("S", false, "GPG/SSH-sign the commit (same as git commit -S)")
+		gpgSignFlag     = flag.Bool("gpg-sign", false, "GPG/SSH-sign the commit")
+		signingKeyFlag  = flag.String("signing-key", "", "Key id to sign the commit with (implies --gpg-sign)")
+		noVerifyFlag    = flag.Bool("no-verify", false, "Bypass pre-commit and commit-msg hooks")
+		amendFlag       = flag.Bool("amend", false, "Amend the previous commit instead of creating a new one")
+		allowEmptyFlag  = flag.Bool("allow-empty", false, "Allow a commit without staged changes")
+		authorFlag      = flag.String("author", "", "Override the commit author ('Name <email>')")
+		dateFlag        = flag.String("date", "", "Override the author date")
+		coAuthorFlags   stringList
+		trailerFlags    stringList
 	)
+	flag.Var(&coAuthorFlags, "co-author", "Add a Co-authored-by trailer (repeatable, e.g. 'Jane Doe <jane@example.com>')")
+	flag.Var(&trailerFlags, "trailer", "Add a custom trailer (repeatable, e.g. 'Reviewed-by: Jane Doe <jane@example.com>')")
 	flag.Parse()
 
 	// Show help if requested
@@ -39,6 +71,29 @@ func main() {
 		log.Fatalf("Failed to initialize application: %v", err)
 	}
 
+	settings := commitSettings{
+		Trailers: git.TrailerOptions{
+			Signoff:   *signoffFlag,
+			CoAuthors: coAuthorFlags,
+		},
+		Options: interfaces.CommitOptions{
+			Sign:       *signFlag || *gpgSignFlag,
+			SigningKey: *signingKeyFlag,
+			NoVerify:   *noVerifyFlag,
+			Amend:      *amendFlag,
+			AllowEmpty: *allowEmptyFlag,
+			Author:     *authorFlag,
+			Date:       *dateFlag,
+		},
+	}
+	for _, raw := range trailerFlags {
+		trailer, err := git.ParseTrailer(raw)
+		if err != nil {
+			log.Fatalf("Invalid --trailer value: %v", err)
+		}
+		settings.Trailers.Extra = append(settings.Trailers.Extra, trailer)
+	}
+
 	// If TUI mode is requested, use the beautiful terminal interface
 	if *tuiFlag {
 		handleTUIMode(service)
@@ -48,13 +103,13 @@ func main() {
 	// Handle different modes (legacy CLI)
 	switch {
 	case *initFlag:
-		handleInit(service)
+		handleInit(service, *nonInteractive)
 	case *reviewFlag:
 		handleCodeReview(service)
-	case *historyFlag != "":
-		handleHistory(service, *historyFlag)
+	case *historyFlag != "" || *allReposFlag:
+		handleHistory(service, *historyFlag, *allReposFlag)
 	case *interactiveFlag:
-		handleInteractive(service)
+		handleInteractive(service, settings)
 	default:
 		// If no flags specified, suggest TUI mode
 		if !hasAnyFlags() {
@@ -68,7 +123,42 @@ func main() {
 			fmt.Println("For help: codegenius --help")
 			return
 		}
-		handleAutoCommit(service)
+		if err := handleAutoCommit(service, settings); err != nil {
+			log.Fatalf("Commit failed: %v", err)
+		}
+	}
+}
+
+// commitSettings groups the per-invocation commit flags
+type commitSettings struct {
+	Trailers git.TrailerOptions
+	Options  interfaces.CommitOptions
+}
+
+// stringList is a flag.Value that collects repeated string flags
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// runSubcommand builds the service and runs a positional subcommand
+func runSubcommand(command cli.Command, args []string) {
+	service, err := buildService()
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+
+	if err := command(service, args); err != nil {
+		if err == flag.ErrHelp {
+			return
+		}
+		log.Fatalf("❌ %v", err)
 	}
 }
 
@@ -87,21 +177,69 @@ func showHelp() {
 
 USAGE:
     codegenius [FLAGS]
+    codegenius <COMMAND> [ARGS]
+
+COMMANDS:
+    reword [<sha>|<range>]  Regenerate and rewrite the message of existing commits
+    squash [--base <ref>]   Synthesize one message for base..HEAD (--apply to squash)
+    split                   Split the staged diff into several logical commits
+    history show            Filter history (--since, --until, --month, --week, --type, --scope, --grep, ...)
+    history export          Export filtered history (--format csv|jsonl|md, --output <file>)
+    history import          Backfill work history from git log (--since, --author, --all)
+    history prune           Apply retention limits (--max-age-days, --max-entries, --subject-only, --dry-run)
+    history delete <id|sha> Remove single entries; IDs are shown by history show
+    history purge           Remove a repository's entries everywhere (--repo <path|name>, --yes)
+    report --standup        Summarise yesterday and today (--weekly for this week, --format slack, --no-ai)
+    timesheet               Estimate hours per day, repo and scope (--month, --format csv|md, --output)
+    config show [--origin]  Print the effective configuration and where each value came from
+    config validate         Check every config file; exits non-zero on errors (for CI)
+    config get <key>        Print an effective value, e.g. config get ai.model
+    config set <key> <val>  Set a value in .codegenius.yaml (--global for the user config)
+    config add <key> <val>  Append to a list, e.g. config add project.scopes billing
+    config edit             Open the config in $EDITOR and validate before saving
+    config migrate          Upgrade config files to the current format (keeps a .bak copy)
 
 FLAGS:
     --tui              Launch beautiful terminal UI (recommended)
     --interactive      Run in interactive mode (legacy)
     --review           Perform code review on staged changes
-    --history [month]  Display work history (e.g., "Dec 2024")
-    --init             Initialize configuration
+    --history [month]  Display work history for a month, year or ISO week (e.g., "Dec 2024")
+    --all-repos        With --history, include every repository in the central store
+    --init             Initialize configuration with a guided wizard
+    --non-interactive  With --init, write the detected settings without asking
     --help             Show this help message
 
+COMMIT FLAGS:
+    --signoff          Add a Signed-off-by trailer for the current committer
+    --co-author <who>  Add a Co-authored-by trailer (repeatable)
+    --trailer <k: v>   Add a custom trailer such as "Refs: #123" (repeatable)
+    -S, --gpg-sign     Sign the commit with your GPG or SSH key
+    --signing-key <id> Sign with a specific key
+    --no-verify        Skip pre-commit and commit-msg hooks
+    --amend            Amend the previous commit
+    --allow-empty      Allow committing without staged changes
+    --author <who>     Override the commit author
+    --date <date>      Override the author date
+
 EXAMPLES:
     codegenius --tui                    # Launch beautiful terminal interface
     codegenius                          # Generate commit message for staged changes
     codegenius --review                 # Review staged changes
     codegenius --history "Dec 2024"     # Show December 2024 history
+    codegenius --signoff --co-author "Jane Doe <jane@example.com>"
+    codegenius reword HEAD              # Replace a "wip" message on the last commit
+    codegenius reword main..HEAD        # Reword every commit on the branch
+    codegenius squash --apply           # Squash the branch into one commit
     codegenius --init                   # Setup configuration
+    codegenius config show --origin     # See which file or variable set each value
+
+CONFIGURATION (later sources win):
+    1. Built-in defaults
+    2. $XDG_CONFIG_HOME/codegenius/config.yaml (default ~/.config/codegenius/config.yaml)
+    3. .codegenius.yaml at the repository root
+    4. .codegenius.yaml in subdirectories, for files beneath them
+    5. CODEGENIUS_<SECTION>_<KEY> environment variables, e.g. CODEGENIUS_AI_MODEL,
+       CODEGENIUS_REVIEW_ENABLED_TYPES=security,style
 
 SETUP:
     1. Get your Gemini API key: https://makersuite.google.com/app/apikey
@@ -143,7 +281,19 @@ func buildService() (*interfaces.Service, error) {
 	// Create configuration manager
 	configManager := config.NewManager()
 	if err := configManager.Load(); err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %v", err)
+		return nil, fmt.Errorf("failed to load configuration: %v (run 'codegenius config validate' for details)", err)
+	}
+	if configErrors := config.Errors(configManager.Validate()); len(configErrors) > 0 {
+		// Invalid values still load as written and unknown keys are ignored, so say which
+		fmt.Fprintf(os.Stderr, "⚠️  Configuration has %d error(s); invalid values are used as written and unknown keys are ignored:\n", len(configErrors))
+		for _, issue := range configErrors {
+			fmt.Fprintf(os.Stderr, "   %s\n", issue)
+		}
+	} else if pending := configManager.PendingMigrations(); len(pending) > 0 {
+		fmt.Fprintf(os.Stderr, "💡 Configuration uses deprecated settings; run 'codegenius config migrate' to update it:\n")
+		for _, migration := range pending {
+			fmt.Fprintf(os.Stderr, "   %s\n", migration)
+		}
 	}
 
 	// Create Git repository
@@ -152,8 +302,17 @@ func buildService() (*interfaces.Service, error) {
 	// Create AI provider
 	aiProvider := ai.NewSessionManager(configManager)
 
-	// Create history manager
-	historyManager := history.NewManager("")
+	// Create history manager, keeping the history in the repository's git
+	// directory wherever codegenius runs from
+	historyFile, err := gitRepo.GetGitPath(history.WorkHistoryFile)
+	if err != nil {
+		historyFile = ""
+	}
+	centralHistory := ""
+	if configManager.GetHistory().CentralStore {
+		centralHistory = history.CentralStorePath()
+	}
+	historyManager := history.NewManager(historyFile, centralHistory, configManager.GetHistory().Retention())
 
 	// Create code reviewer
 	codeReviewer := review.NewReviewer(configManager, aiProvider)
@@ -170,20 +329,29 @@ func buildService() (*interfaces.Service, error) {
 	return service, nil
 }
 
-func handleInit(service *interfaces.Service) {
-	if err := service.Config.Initialize(); err != nil {
+func handleInit(service *interfaces.Service, nonInteractive bool) {
+	if !nonInteractive {
+		if err := tui.NewTUI(service).RunInitWizard(); err != nil {
+			log.Fatalf("Failed to initialize configuration: %v", err)
+		}
+		return
+	}
+
+	options := service.Config.Suggest()
+	if err := service.Config.InitializeWith(options); err != nil {
 		log.Fatalf("Failed to initialize configuration: %v", err)
 	}
-	fmt.Println("✅ Configuration initialized successfully!")
+	fmt.Printf("✅ Configuration initialized for %s (%s)\n", options.Name, strings.Join(options.Languages, ", "))
+	fmt.Printf("📦 Scopes: %s\n", strings.Join(options.Scopes, ", "))
 }
 
 func handleCodeReview(service *interfaces.Service) {
-	diff, err := service.Git.GetDiff()
+	diff, err := service.Git.GetStagedDiff()
 	if err != nil {
 		log.Fatalf("Failed to get git diff: %v", err)
 	}
 
-	if diff == "" {
+	if diff.IsEmpty() {
 		fmt.Println("⚠️  No changes detected for review.")
 		return
 	}
@@ -193,7 +361,14 @@ func handleCodeReview(service *interfaces.Service) {
 	}
 }
 
-func handleHistory(service *interfaces.Service, monthYear string) {
+func handleHistory(service *interfaces.Service, monthYear string, allRepos bool) {
+	if allRepos {
+		if err := service.History.DisplayAcrossRepos(monthYear); err != nil {
+			log.Fatalf("Failed to display history: %v", err)
+		}
+		return
+	}
+
 	if err := service.History.Load(); err != nil {
 		log.Fatalf("Failed to load work history: %v", err)
 	}
@@ -203,7 +378,7 @@ func handleHistory(service *interfaces.Service, monthYear string) {
 	}
 }
 
-func handleInteractive(service *interfaces.Service) {
+func handleInteractive(service *interfaces.Service, settings commitSettings) {
 	fmt.Println("=== CodeGenius Interactive Mode (Legacy) ===")
 	fmt.Println("💡 Tip: Try the new TUI mode with: codegenius --tui")
 	fmt.Println()
@@ -222,7 +397,7 @@ func handleInteractive(service *interfaces.Service) {
 
 		switch command {
 		case "commit":
-			if err := handleAutoCommit(service); err != nil {
+			if err := handleAutoCommit(service, settings); err != nil {
 				fmt.Printf("❌ Commit failed: %v\n", err)
 			}
 		case "review":
@@ -231,7 +406,7 @@ func handleInteractive(service *interfaces.Service) {
 			fmt.Print("Enter month-year (e.g., 'Dec 2024') or press Enter for all: ")
 			var monthYear string
 			fmt.Scanln(&monthYear)
-			handleHistory(service, monthYear)
+			handleHistory(service, monthYear, false)
 		case "stats":
 			handleStats(service)
 		case "exit":
@@ -243,27 +418,28 @@ func handleInteractive(service *interfaces.Service) {
 	}
 }
 
-func handleAutoCommit(service *interfaces.Service) error {
+func handleAutoCommit(service *interfaces.Service, settings commitSettings) error {
 	// Check for staged changes
 	hasStaged, err := service.Git.HasStagedChanges()
 	if err != nil {
 		return fmt.Errorf("error checking staged changes: %v", err)
 	}
 
-	if !hasStaged {
+	// A message-only amend needs nothing staged
+	if !hasStaged && !settings.Options.AllowEmpty && !settings.Options.Amend {
 		fmt.Println("⚠️  No staged changes detected. Please stage your changes first with 'git add'.")
 		return nil
 	}
 
-	// Get git information
-	diff, err := service.Git.GetDiff()
-	if err != nil {
-		return fmt.Errorf("error getting git diff: %v", err)
+	// Get git information; an amend describes HEAD's changes plus anything staged
+	var diff *interfaces.Diff
+	if settings.Options.Amend {
+		diff, err = service.Git.GetAmendDiff()
+	} else {
+		diff, err = service.Git.GetStagedDiff()
 	}
-
-	files, err := service.Git.GetChangedFiles()
 	if err != nil {
-		return fmt.Errorf("error getting changed files: %v", err)
+		return fmt.Errorf("error getting git diff: %v", err)
 	}
 
 	branchName, err := service.Git.GetCurrentBranch()
@@ -271,8 +447,24 @@ func handleAutoCommit(service *interfaces.Service) error {
 		return fmt.Errorf("error getting current branch: %v", err)
 	}
 
+	files := diff.Paths()
+	if !settings.Options.Amend {
+		if files, err = service.Git.GetChangedFiles(); err != nil {
+			return fmt.Errorf("error getting changed files: %v", err)
+		}
+	}
+
+	commitScope, scopes := scope.ForChanges(service.Git, service.Config.GetProject(), files)
+	if len(scopes) > 1 {
+		fmt.Printf("⚠️  Staged changes span multiple scopes (%s); consider 'codegenius split'.\n", strings.Join(scopes, ", "))
+	}
+
 	// Generate commit message with AI
-	message, err := service.AI.GenerateCommitMessage(diff, files, branchName, "")
+	message, err := service.AI.GenerateCommitMessage(interfaces.CommitRequest{
+		Diff:   diff,
+		Branch: branchName,
+		Scope:  commitScope,
+	})
 	if err != nil {
 		return fmt.Errorf("error generating commit message: %v", err)
 	}
@@ -285,17 +477,9 @@ func handleAutoCommit(service *interfaces.Service) error {
 
 	switch response {
 	case "y", "Y", "yes", "Yes":
-		if err := service.Git.CommitWithMessage(message); err != nil {
-			return fmt.Errorf("error committing: %v", err)
-		}
-
-		// Load and add to work history
-		if err := service.History.Load(); err != nil {
-			log.Printf("Warning: Failed to load work history: %v", err)
-		} else if err := service.History.AddEntry(message); err != nil {
-			log.Printf("Warning: Failed to update work history: %v", err)
+		if err := commitAndRecord(service, message, interfaces.SourceGenerated, settings); err != nil {
+			return err
 		}
-
 		fmt.Println("✅ Changes committed successfully!")
 	case "e", "E", "edit", "Edit":
 		editedMessage, err := service.Git.EditCommitMessage(message)
@@ -303,17 +487,10 @@ func handleAutoCommit(service *interfaces.Service) error {
 			return fmt.Errorf("error editing commit message: %v", err)
 		}
 
-		if err := service.Git.CommitWithMessage(editedMessage); err != nil {
-			return fmt.Errorf("error committing: %v", err)
+		source := interfaces.MessageSource(message, editedMessage)
+		if err := commitAndRecord(service, editedMessage, source, settings); err != nil {
+			return err
 		}
-
-		// Load and add to work history
-		if err := service.History.Load(); err != nil {
-			log.Printf("Warning: Failed to load work history: %v", err)
-		} else if err := service.History.AddEntry(editedMessage); err != nil {
-			log.Printf("Warning: Failed to update work history: %v", err)
-		}
-
 		fmt.Println("✅ Changes committed successfully!")
 	default:
 		fmt.Println("🚫 Commit cancelled.")
@@ -322,6 +499,41 @@ func handleAutoCommit(service *interfaces.Service) error {
 	return nil
 }
 
+// commitAndRecord appends trailers, commits and records the commit in work
+// history, noting whether the message was generated or edited
+func commitAndRecord(service *interfaces.Service, message, source string, settings commitSettings) error {
+	trailers, err := git.CollectTrailers(service.Git, service.Config.GetCommit(), settings.Trailers)
+	if err != nil {
+		return fmt.Errorf("error collecting commit trailers: %v", err)
+	}
+
+	fullMessage, err := service.Git.ApplyTrailers(message, trailers)
+	if err != nil {
+		return err
+	}
+
+	opts := git.ResolveCommitOptions(service.Config.GetCommit(), settings.Options)
+
+	// An amend replaces HEAD, so its history entry is replaced too
+	replaces := ""
+	if opts.Amend {
+		replaces, _ = service.Git.ResolveRevision("HEAD")
+	}
+
+	if err := service.Git.CommitWithMessage(fullMessage, opts); err != nil {
+		return fmt.Errorf("error committing: %v", err)
+	}
+
+	// Load and add to work history
+	if err := service.History.Load(); err != nil {
+		log.Printf("Warning: Failed to load work history: %v", err)
+	} else if err := service.History.RecordCommit(service.Git, message, source, replaces); err != nil {
+		log.Printf("Warning: Failed to update work history: %v", err)
+	}
+
+	return nil
+}
+
 func handleStats(service *interfaces.Service) {
 	if err := service.History.Load(); err != nil {
 		fmt.Printf("❌ Failed to load work history: %v\n", err)
@@ -333,18 +545,31 @@ func handleStats(service *interfaces.Service) {
 	fmt.Println("\n📊 CodeGenius Statistics")
 	fmt.Println(strings.Repeat("═", 30))
 
-	if totalCommits, ok := stats["total_commits"].(int); ok {
-		fmt.Printf("📈 Total commits: %d\n", totalCommits)
+	fmt.Printf("📈 Total commits: %d\n", stats.TotalCommits)
+	if stats.TotalCommits == 0 {
+		fmt.Println()
+		return
 	}
-
-	if mostActive, ok := stats["most_active_month"].(string); ok && mostActive != "" {
-		fmt.Printf("🏆 Most active month: %s\n", mostActive)
+	if stats.MostActiveMonth != "" {
+		fmt.Printf("🏆 Most active month: %s\n", stats.MostActiveMonth)
+	}
+	fmt.Printf("🔥 Streak: %d day(s) (longest %d)\n", stats.CurrentStreak, stats.LongestStreak)
+	fmt.Printf("✏️  Average message: %.0f characters\n", stats.AverageMessage)
+	if rate := stats.AcceptanceRate(); rate >= 0 {
+		fmt.Printf("🤖 AI messages accepted unedited: %.0f%% (%d of %d)\n", rate*100, stats.Generated, stats.Generated+stats.Edited)
 	}
 
-	if breakdown, ok := stats["monthly_breakdown"].(map[string]int); ok && len(breakdown) > 0 {
+	if len(stats.Monthly) > 0 {
 		fmt.Println("\n📅 Monthly breakdown:")
-		for month, count := range breakdown {
-			fmt.Printf("  %s: %d commits\n", month, count)
+		for _, month := range stats.Monthly {
+			fmt.Printf("  %s: %d commits\n", month.Name, month.Count)
+		}
+	}
+
+	if len(stats.Types) > 0 {
+		fmt.Println("\n🏷️  Commit types:")
+		for _, count := range stats.Types {
+			fmt.Printf("  %s: %d\n", count.Name, count.Count)
 		}
 	}
 